@@ -7,10 +7,13 @@ import (
 	"github.com/MysteriousPotato/nitecache/inmem"
 	"github.com/MysteriousPotato/nitecache/servicepb"
 	"google.golang.org/grpc/credentials/insecure"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/MysteriousPotato/nitecache/hashring"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 )
@@ -20,6 +23,11 @@ var (
 	ErrTableNotFound  = errors.New("table not found")
 	ErrMissingMembers = errors.New("peers must contain at least one member")
 	ErrCacheDestroyed = errors.New("can't use cache after tear down")
+	// ErrDiscoveryID is returned by [NewCache] when using a [DNSDiscovery] if the ID of the current member isn't its normalised address,
+	// i.e. its address with a lowercase host, without trailing dot, and with IPs in their canonical form.
+	//
+	// DNSDiscovery identifies members by their address, so every member must agree on the ID of the current member.
+	ErrDiscoveryID = errors.New("member ID must be its normalised address when using DNS discovery")
 )
 
 type (
@@ -28,35 +36,86 @@ type (
 	//
 	// Refer to [NewCache] for creating an instance.
 	Cache struct {
-		ring                 *hashring.Ring
-		self                 Member
-		clients              clients
-		clientMu             *sync.Mutex
-		tables               map[string]table
-		tablesMu             *sync.Mutex
-		metrics              *metrics
-		virtualNodes         int
-		hashFunc             hashring.HashFunc
-		timeout              time.Duration
-		members              []Member
+		placement         hashring.Placement
+		placementStrategy PlacementStrategy
+		self              Member
+		clients           clients
+		clientMu          *sync.RWMutex
+		tables            map[string]table
+		tablesMu          *sync.Mutex
+		metrics           *metrics
+		virtualNodes      int
+		replicationFactor int
+		hashFunc          hashring.HashFunc
+		timeout           time.Duration
+		members           []Member
+		// Members of the current hashring, i.e. excluding dead members
+		ringMembers          []Member
 		grpcOpts             []grpc.ServerOption
 		service              server
 		transportCredentials credentials.TransportCredentials
+		membersMu            *sync.Mutex
+		discovery            Discovery
+		stopDiscovery        func()
+		gossip               *gossip
+		stopGossip           func()
+		snapshotPath         string
+		restored             map[string]map[string]inmem.Item[[]byte]
+		tracer               trace.Tracer
+		loads                *loadTracker
+		zones                *hashring.ZoneAware
 	}
 )
 
+// PlacementStrategy determines how keys are assigned to members. Refer to [PlacementOpt].
+type PlacementStrategy int
+
+const (
+	// RingPlacement uses a consistent hash ring, with a number of virtual nodes per member set by [VirtualNodeOpt].
+	//
+	// Refer to [hashring.Ring] for more details.
+	RingPlacement PlacementStrategy = iota
+	// RendezvousPlacement uses rendezvous hashing, which distributes keys evenly without virtual nodes.
+	//
+	// Refer to [hashring.Rendezvous] for more details.
+	RendezvousPlacement
+	// JumpPlacement uses jump consistent hashing, which distributes keys evenly without virtual nodes,
+	// but moves more keys than necessary unless the member with the greatest ID changes.
+	//
+	// Refer to [hashring.Jump] for more details.
+	JumpPlacement
+)
+
 type Member struct {
 	ID   string
 	Addr string
+	// Weight scales the share of keys assigned to the member, i.e. a member with a weight of 2 gets twice as many keys.
+	//
+	// Only used by [RingPlacement], where it scales the number of virtual nodes of the member.
+	// Defaults to 1
+	Weight float64
+	// Zone identifies the failure domain of the member, i.e. an availability zone or a rack.
+	//
+	// Replicas of a key are spread across as many zones as possible (see [ReplicationFactorOpt]),
+	// and gets are served by a replica in the same zone as the current member whenever possible.
+	Zone string
 }
 
 type table interface {
 	getLocally(ctx context.Context, key string) (inmem.Item[[]byte], bool, error)
-	putLocally(key string, item inmem.Item[[]byte]) error
+	putLocally(ctx context.Context, key string, item inmem.Item[[]byte]) (inmem.Item[[]byte], error)
+	compareAndSwapLocally(ctx context.Context, key string, expectedVersion uint64, item inmem.Item[[]byte]) (inmem.Item[[]byte], bool, error)
 	evictLocally(key string) error
-	evictAllLocally(keys []string)
-	callLocally(ctx context.Context, key, procedure string, args []byte) (inmem.Item[[]byte], error)
-	tearDown()
+	evictAllLocally(keys []string) error
+	callLocally(ctx context.Context, key, procedure string, args []byte) (inmem.Item[[]byte], []byte, error)
+	rebalanceLocally(key string, item inmem.Item[[]byte]) error
+	rebalance(ctx context.Context, prev hashring.Placement) error
+	values() (map[string]inmem.Item[[]byte], error)
+	restore(items map[string]inmem.Item[[]byte]) error
+	invalidateLocally(key string, item *inmem.Item[[]byte])
+	stop()
+	getMetrics() TableMetrics
+	tearDown() error
 }
 
 // NewCache Creates a new [Cache] instance
@@ -78,21 +137,29 @@ func NewCache(self Member, peers []Member, opts ...CacheOpt) (*Cache, error) {
 	c := &Cache{
 		self:                 self,
 		clients:              clients{},
-		clientMu:             &sync.Mutex{},
+		clientMu:             &sync.RWMutex{},
 		tables:               make(map[string]table),
 		tablesMu:             &sync.Mutex{},
+		restored:             map[string]map[string]inmem.Item[[]byte]{},
 		metrics:              newMetrics(),
 		virtualNodes:         32,
+		replicationFactor:    1,
 		hashFunc:             hashring.DefaultHashFunc,
 		timeout:              time.Second * 3,
 		members:              []Member{},
+		membersMu:            &sync.Mutex{},
 		transportCredentials: insecure.NewCredentials(),
+		tracer:               newTracer(),
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if _, ok := c.discovery.(*DNSDiscovery); ok && self.ID != normalizeAddr(self.Addr) {
+		return nil, fmt.Errorf("%w: expected ID %q, got %q", ErrDiscoveryID, normalizeAddr(self.Addr), self.ID)
+	}
+
 	var peersIncludeSelf bool
 	for _, peer := range peers {
 		if peer.ID == self.ID {
@@ -114,6 +181,40 @@ func NewCache(self Member, peers []Member, opts ...CacheOpt) (*Cache, error) {
 		return nil, err
 	}
 
+	if c.snapshotPath != "" {
+		if err := c.restoreFromFile(c.snapshotPath); err != nil {
+			return nil, fmt.Errorf("unable to restore snapshot: %w", err)
+		}
+	}
+
+	if c.gossip != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			c.gossip.run(ctx)
+		}()
+
+		c.stopGossip = func() {
+			cancel()
+			<-done
+		}
+	}
+
+	if c.discovery != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			c.discovery.Watch(ctx, c.setDiscoveredPeers)
+		}()
+
+		c.stopDiscovery = func() {
+			cancel()
+			<-done
+		}
+	}
+
 	return c, nil
 }
 
@@ -125,6 +226,27 @@ func VirtualNodeOpt(nodes int) func(c *Cache) {
 	}
 }
 
+// PlacementOpt sets the strategy used to assign keys to members.
+//
+// Every member must use the same strategy.
+// Defaults to [RingPlacement]
+func PlacementOpt(strategy PlacementStrategy) func(c *Cache) {
+	return func(c *Cache) {
+		c.placementStrategy = strategy
+	}
+}
+
+// ReplicationFactorOpt sets the number of members each key is stored on.
+//
+// Writes are sent to every replica, while reads fall back to the next replica when the primary owner is unreachable.
+// Replicas are spread across as many zones as possible (see [Member.Zone]).
+// Defaults to 1
+func ReplicationFactorOpt(n int) func(c *Cache) {
+	return func(c *Cache) {
+		c.replicationFactor = n
+	}
+}
+
 // TimeoutOpt sets the timeout for grpc clients
 // Defaults to 3 seconds
 func TimeoutOpt(timeout time.Duration) func(c *Cache) {
@@ -164,7 +286,41 @@ func (c *Cache) GetMetrics() (Metrics, error) {
 	return c.metrics.getCopy(), nil
 }
 
+// GetTableMetrics returns a copy of the current Metrics of every [Table], indexed by table name.
+func (c *Cache) GetTableMetrics() (map[string]TableMetrics, error) {
+	if c.isZero() {
+		return nil, ErrCacheDestroyed
+	}
+
+	c.tablesMu.Lock()
+	defer c.tablesMu.Unlock()
+
+	tablesMetrics := make(map[string]TableMetrics, len(c.tables))
+	for name, t := range c.tables {
+		tablesMetrics[name] = t.getMetrics()
+	}
+	return tablesMetrics, nil
+}
+
+// GetRingMembers returns the ID of the members currently on the hashring.
+//
+// Unlike the members passed to [Cache.SetPeers], members that were declared dead (see [GossipOpt]) are excluded.
+func (c *Cache) GetRingMembers() ([]string, error) {
+	if c.isZero() {
+		return nil, ErrCacheDestroyed
+	}
+	return c.placement.Members(), nil
+}
+
 // SetPeers will update the cache members to the new value.
+//
+// Once the hashring is updated, keys stored locally are sent to the members that became their owners (i.e. a new replica),
+// and keys that are no longer owned by the current member are dropped locally.
+// Keys that could not be sent are kept locally and will be retried on the next call to SetPeers.
+//
+// SetPeers returns once every local key was scanned and moved if needed.
+// When members are updated by [DiscoveryOpt] or [GossipOpt], this happens on their background goroutine,
+// so the next membership change is only applied once the current rebalance is done.
 func (c *Cache) SetPeers(peers []Member) error {
 	if c.isZero() {
 		return ErrCacheDestroyed
@@ -194,31 +350,29 @@ func (c *Cache) SetPeers(peers []Member) error {
 		peers = append(peers, c.self)
 	}
 
-	members := make([]string, len(peers))
-	for i, p := range peers {
-		members[i] = p.ID
-	}
-
-	var err error
-	if c.ring == nil {
-		c.ring, err = hashring.New(hashring.Opt{
-			Members:      members,
-			VirtualNodes: c.virtualNodes,
-			HashFunc:     c.hashFunc,
-		})
-		if err != nil {
-			return fmt.Errorf("unable to create hashring: %w", err)
-		}
-	} else {
-		if err := c.ring.SetMembers(members); err != nil {
-			return fmt.Errorf("unable to update hashring: %w", err)
+	c.membersMu.Lock()
+	c.members = peers
+	if c.gossip != nil {
+		ids := make([]string, len(peers))
+		for i, p := range peers {
+			ids[i] = p.ID
 		}
+		c.gossip.setMembers(ids)
+	}
+	prev, err := c.unsafeSetRing()
+	c.membersMu.Unlock()
+	if err != nil {
+		return err
 	}
 
 	if err := c.setClients(peers); err != nil {
 		return err
 	}
 
+	if err := c.rebalance(context.Background(), prev); err != nil {
+		return fmt.Errorf("unable to rebalance keys: %w", err)
+	}
+
 	return nil
 }
 
@@ -230,17 +384,37 @@ func (c *Cache) TearDown() error {
 		return ErrCacheDestroyed
 	}
 
+	if c.stopDiscovery != nil {
+		c.stopDiscovery()
+	}
+	if c.stopGossip != nil {
+		c.stopGossip()
+	}
+	for _, t := range c.tables {
+		t.stop()
+	}
+
 	var errs []error
+	c.clientMu.Lock()
 	for _, client := range c.clients {
 		if err := client.conn.Close(); err != nil {
 			errs = append(errs, err)
 		}
 	}
+	c.clientMu.Unlock()
 
 	c.service.server.GracefulStop()
 
+	if c.snapshotPath != "" {
+		if err := c.snapshotToFile(c.snapshotPath); err != nil {
+			errs = append(errs, fmt.Errorf("unable to write snapshot: %w", err))
+		}
+	}
+
 	for i := range c.tables {
-		c.tables[i].tearDown()
+		if err := c.tables[i].tearDown(); err != nil {
+			errs = append(errs, err)
+		}
 	}
 	*c = Cache{}
 
@@ -261,8 +435,13 @@ func (c *Cache) HealthCheckPeers(ctx context.Context) error {
 		return ErrCacheDestroyed
 	}
 
+	// Clients may be replaced concurrently by SetPeers, i.e. following discovery or gossip updates
+	c.clientMu.RLock()
+	clients := maps.Clone(c.clients)
+	c.clientMu.RUnlock()
+
 	var errs []error
-	for _, client := range c.clients {
+	for _, client := range clients {
 		if _, err := client.HealthCheck(ctx, &servicepb.Empty{}); err != nil {
 			errs = append(errs, err)
 		}
@@ -270,6 +449,136 @@ func (c *Cache) HealthCheckPeers(ctx context.Context) error {
 	return errors.Join(errs...)
 }
 
+// Update the hashring and send keys to their new owners after a member was declared dead or recovered
+func (c *Cache) updateMembers() error {
+	c.membersMu.Lock()
+	prev, err := c.unsafeSetRing()
+	c.membersMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return c.rebalance(context.Background(), prev)
+}
+
+// Create or update the hashring using all members that are not known to be dead.
+//
+// Returns a placement of the members of the previous hashring, or nil if the hashring was just created.
+//
+// Make sure to lock membersMu before using this
+func (c *Cache) unsafeSetRing() (hashring.Placement, error) {
+	var members []Member
+	for _, p := range c.members {
+		if c.gossip == nil || !c.gossip.isDead(p.ID) {
+			members = append(members, p)
+		}
+	}
+
+	if c.placement == nil {
+		zones, err := c.newZoneAware(members)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create hashring: %w", err)
+		}
+
+		c.zones = zones
+		c.placement = c.zones
+		if c.loads != nil {
+			c.placement = hashring.NewBoundedLoads(c.zones, c.loads.epsilon, c.loads.load)
+		}
+		c.ringMembers = members
+		return nil, nil
+	}
+
+	prev, err := c.newZoneAware(c.ringMembers)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create previous hashring: %w", err)
+	}
+
+	ids, weights, zones := ringConfig(members)
+	c.zones.SetZones(zones)
+	if err := c.zones.SetWeights(weights); err != nil {
+		return nil, fmt.Errorf("unable to update hashring weights: %w", err)
+	}
+	if err := c.placement.SetMembers(ids); err != nil {
+		return nil, fmt.Errorf("unable to update hashring: %w", err)
+	}
+	c.ringMembers = members
+	return prev, nil
+}
+
+// Create a zone-aware placement of the given members, ignoring loads
+func (c *Cache) newZoneAware(members []Member) (*hashring.ZoneAware, error) {
+	ids, weights, zones := ringConfig(members)
+	placement, err := c.newPlacement(ids, weights)
+	if err != nil {
+		return nil, err
+	}
+	return hashring.NewZoneAware(placement, zones), nil
+}
+
+// Returns the IDs, weights and zones of the given members
+func ringConfig(members []Member) ([]string, map[string]float64, map[string]string) {
+	ids := make([]string, 0, len(members))
+	weights := map[string]float64{}
+	zones := map[string]string{}
+	for _, p := range members {
+		ids = append(ids, p.ID)
+		if p.Weight > 0 {
+			weights[p.ID] = p.Weight
+		}
+		if p.Zone != "" {
+			zones[p.ID] = p.Zone
+		}
+	}
+	return ids, weights, zones
+}
+
+func (c *Cache) newPlacement(members []string, weights map[string]float64) (hashring.Placement, error) {
+	opt := hashring.Opt{
+		Members:      members,
+		VirtualNodes: c.virtualNodes,
+		HashFunc:     c.hashFunc,
+		Weights:      weights,
+	}
+
+	switch c.placementStrategy {
+	case RendezvousPlacement:
+		return hashring.NewRendezvous(opt)
+	case JumpPlacement:
+		return hashring.NewJump(opt)
+	default:
+		return hashring.New(opt)
+	}
+}
+
+// Discovered members are matched with self by ID, so that self keeps its own address, weight and zone
+func (c *Cache) setDiscoveredPeers(peers []Member) error {
+	peers = slices.Clone(peers)
+	for i, p := range peers {
+		if p.ID == c.self.ID {
+			peers[i] = c.self
+		}
+	}
+	return c.SetPeers(peers)
+}
+
+// Send keys to the members that became their owners following a change of the hashring.
+//
+// prev is a placement of the members of the previous hashring, if any.
+func (c *Cache) rebalance(ctx context.Context, prev hashring.Placement) error {
+	c.tablesMu.Lock()
+	tables := maps.Clone(c.tables)
+	c.tablesMu.Unlock()
+
+	var errs []error
+	for _, t := range tables {
+		if err := t.rebalance(ctx, prev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (c *Cache) getTable(name string) (table, error) {
 	t, ok := c.tables[name]
 	if !ok {
@@ -280,6 +589,9 @@ func (c *Cache) getTable(name string) (table, error) {
 }
 
 func (c *Cache) getClient(p string) (*client, error) {
+	c.clientMu.RLock()
+	defer c.clientMu.RUnlock()
+
 	cl, ok := c.clients[p]
 	if !ok {
 		return nil, fmt.Errorf("unable to find peer client with ID %v", p)
@@ -311,7 +623,7 @@ func (c *Cache) setClients(peers []Member) error {
 		if _, ok := c.clients[id]; ok {
 			continue
 		}
-		client, err := newClient(p.Addr, c)
+		client, err := newClient(p, c)
 		if err != nil {
 			return err
 		}
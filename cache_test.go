@@ -3,11 +3,17 @@ package nitecache_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/MysteriousPotato/nitecache"
+	"github.com/MysteriousPotato/nitecache/hashring"
+	"github.com/MysteriousPotato/nitecache/inmem"
 	test "github.com/MysteriousPotato/nitecache/test_utils"
 )
 
@@ -47,15 +53,50 @@ func TestCache_SetPeers(t *testing.T) {
 		})
 	}
 
-	if err := c.TearDown(); err != nil {
-		t.Fatal(err)
-	}
+	test.TearDown(t, c)
 
 	if err := c.SetPeers(nil); !errors.Is(err, nitecache.ErrCacheDestroyed) {
 		t.Fatalf("expected err: %v\ngot:%v", nitecache.ErrCacheDestroyed, err)
 	}
 }
 
+func TestCache_HealthCheckPeersDuringSetPeers(t *testing.T) {
+	self := nitecache.Member{ID: "1", Addr: test.GetUniqueAddr()}
+	peer := nitecache.Member{ID: "2", Addr: test.GetUniqueAddr()}
+	c, err := nitecache.NewCache(self, nil, nitecache.TimeoutOpt(time.Millisecond*10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDown(t, c)
+
+	// Clients are replaced while being checked, as with discovery or gossip updates
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			peers := []nitecache.Member{self}
+			if i%2 == 0 {
+				peers = append(peers, peer)
+			}
+			if err := c.SetPeers(peers); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+			_ = c.HealthCheckPeers(ctx)
+		}
+	}
+}
+
 func TestSingleNodeCacheTable(t *testing.T) {
 	ctx := context.Background()
 	self := nitecache.Member{
@@ -126,9 +167,7 @@ func TestSingleNodeCacheTable(t *testing.T) {
 		t.Fatalf("\nexpect: %v\ngot: %v", expected, got)
 	}
 
-	if err := c.TearDown(); err != nil {
-		t.Fatal(err)
-	}
+	test.TearDown(t, c)
 
 	if _, err := table.Get(ctx, ""); !errors.Is(err, nitecache.ErrCacheDestroyed) {
 		t.Fatalf("expected err: %v\ngot:%v", nitecache.ErrCacheDestroyed, err)
@@ -258,3 +297,1042 @@ func TestMultiNodeCacheTable(t *testing.T) {
 		}
 	}
 }
+
+func TestReplicatedCacheTable(t *testing.T) {
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "3",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[string], len(members))
+	for i, m := range members {
+		c, err := nitecache.NewCache(m, members,
+			nitecache.VirtualNodeOpt(1),
+			nitecache.HashFuncOpt(test.SimpleHashFunc),
+			nitecache.ReplicationFactorOpt(2),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+				return
+			}
+		}()
+
+		caches[i] = c
+		tables[i] = nitecache.NewTable[string]("test").Build(c)
+	}
+
+	for _, c := range caches {
+		test.WaitForServer(t, c)
+	}
+
+	// Key "2" is owned by member "2" and replicated on member "3"
+	ctx := context.Background()
+	if err := tables[0].Put(ctx, "2", "value", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, i := range []int{1, 2} {
+		v, err := tables[i].GetHot("2")
+		if err != nil {
+			t.Fatalf("expected replica %s to hold the key, got err: %v", members[i].ID, err)
+		}
+		if v != "value" {
+			t.Fatalf("expected value %q on replica %s, got: %q", "value", members[i].ID, v)
+		}
+	}
+
+	test.TearDown(t, caches[1])
+
+	v, err := tables[0].Get(ctx, "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "value" {
+		t.Fatalf("expected value %q from replica, got: %q", "value", v)
+	}
+
+	got, err := tables[0].GetMany(ctx, []string{"2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]string{"2": "value"}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected: %v\ngot: %v", expected, got)
+	}
+
+	if err := tables[0].Evict(ctx, "1"); err == nil {
+		t.Fatal("expected error when evicting from an unreachable replica")
+	}
+
+	for _, i := range []int{0, 2} {
+		test.TearDown(t, caches[i])
+	}
+}
+
+func TestReplicationErr(t *testing.T) {
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+
+	caches := make([]*nitecache.Cache, len(members))
+	for i, m := range members {
+		c, err := nitecache.NewCache(m, members,
+			nitecache.VirtualNodeOpt(1),
+			nitecache.HashFuncOpt(test.SimpleHashFunc),
+			nitecache.ReplicationFactorOpt(2),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer test.TearDown(t, c)
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		caches[i] = c
+	}
+	for _, c := range caches {
+		test.WaitForServer(t, c)
+	}
+
+	// The table only exists on member "1", so copies to member "2" fail
+	table := nitecache.NewTable[int]("test").
+		WithProcedure("increment", func(_ context.Context, v int, _ []byte) (int, time.Duration, error) {
+			return v + 1, 0, nil
+		}).
+		Build(caches[0])
+
+	// Key "1" is owned by member "1" and replicated on member "2"
+	ctx := context.Background()
+	for i := 1; i <= 2; i++ {
+		v, err := table.Call(ctx, "1", "increment", nil)
+		if !errors.Is(err, nitecache.ErrReplication) {
+			t.Fatalf("expected error %v, got: %v", nitecache.ErrReplication, err)
+		}
+		if v != i {
+			t.Fatalf("expected value %d, got: %d", i, v)
+		}
+	}
+
+	version, err := table.CompareAndSwap(ctx, "1", 2, 10, 0)
+	if !errors.Is(err, nitecache.ErrReplication) {
+		t.Fatalf("expected error %v, got: %v", nitecache.ErrReplication, err)
+	}
+	if version != 3 {
+		t.Fatalf("expected version %d, got: %d", 3, version)
+	}
+	if v, err := table.Get(ctx, "1"); err != nil || v != 10 {
+		t.Fatalf("expected value %d, got: %d, err: %v", 10, v, err)
+	}
+}
+
+func TestCache_SetPeersRebalance(t *testing.T) {
+	ctx := context.Background()
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+	opts := []nitecache.CacheOpt{
+		nitecache.VirtualNodeOpt(1),
+		nitecache.HashFuncOpt(test.SimpleHashFunc),
+	}
+
+	c1, err := nitecache.NewCache(members[0], members[:1], opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		if err := c1.ListenAndServe(); err != nil {
+			t.Error(err)
+		}
+	}()
+	table1 := nitecache.NewTable[string]("test").Build(c1)
+
+	for _, key := range []string{"1", "2", "3"} {
+		if err := table1.Put(ctx, key, "value-"+key, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c2, err := nitecache.NewCache(members[1], members, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		if err := c2.ListenAndServe(); err != nil {
+			t.Error(err)
+		}
+	}()
+	table2 := nitecache.NewTable[string]("test").Build(c2)
+	test.WaitForServer(t, c2)
+
+	// Key "2" is now owned by member "2"
+	if err := c1.SetPeers(members); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := table2.GetHot("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "value-2" {
+		t.Fatalf("expected value %q on new owner, got: %q", "value-2", v)
+	}
+
+	v, err = table2.Get(ctx, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "value-1" {
+		t.Fatalf("expected value %q from previous owner, got: %q", "value-1", v)
+	}
+
+	test.TearDown(t, c2)
+	if err := c1.SetPeers(members[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Key "2" should have been dropped from member "1" during rebalancing
+	expected := map[string]error{"1": nil, "2": nitecache.ErrKeyNotFound, "3": nil}
+	for key, expectedErr := range expected {
+		if _, err := table1.Get(ctx, key); !errors.Is(err, expectedErr) {
+			t.Fatalf("expected err: %v for key %s\ngot:%v", expectedErr, key, err)
+		}
+	}
+
+	test.TearDown(t, c1)
+}
+
+func TestCache_SetPeersRebalanceReplicas(t *testing.T) {
+	ctx := context.Background()
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[string], len(members))
+	for i, m := range members {
+		// Member "2" joins once values are stored
+		c, err := nitecache.NewCache(m, members[:i+1],
+			nitecache.VirtualNodeOpt(1),
+			nitecache.HashFuncOpt(test.SimpleHashFunc),
+			nitecache.ReplicationFactorOpt(2),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer test.TearDown(t, c)
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		caches[i] = c
+		tables[i] = nitecache.NewTable[string]("test").Build(c)
+		test.WaitForServer(t, c)
+
+		if i == 0 {
+			for key := 0; key < 10; key++ {
+				if err := tables[0].Put(ctx, strconv.Itoa(key), "value", time.Hour); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	// Member "1" is still an owner of every key, but must copy them to member "2", which became their other replica
+	if err := caches[0].SetPeers(members); err != nil {
+		t.Fatal(err)
+	}
+
+	for key := 0; key < 10; key++ {
+		for i, table := range tables {
+			if _, err := table.GetHot(strconv.Itoa(key)); err != nil {
+				t.Fatalf("expected member %s to hold key %d, got err: %v", members[i].ID, key, err)
+			}
+		}
+	}
+}
+
+func TestCache_SetPeersNotEnumerable(t *testing.T) {
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+
+	c, err := nitecache.NewCache(members[0], members[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := nitecache.NewTable[string]("test").
+		WithStorage(&listlessStorage{items: map[string]inmem.Item[[]byte]{}}).
+		Build(c)
+	if err := table.Put(context.Background(), "1", "value-1", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys can't be listed, so they aren't moved, but the membership change must still be applied
+	if err := c.SetPeers(members); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.GetRingMembers(); err != nil || len(got) != 2 {
+		t.Fatalf("expected 2 members, got: %v, err: %v", got, err)
+	}
+
+	test.TearDown(t, c)
+}
+
+func TestBatchCacheTable(t *testing.T) {
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "3",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[string], len(members))
+	for i, m := range members {
+		c, err := nitecache.NewCache(m, members,
+			nitecache.VirtualNodeOpt(1),
+			nitecache.HashFuncOpt(test.SimpleHashFunc),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+				return
+			}
+		}()
+
+		caches[i] = c
+		tables[i] = nitecache.NewTable[string]("test").Build(c)
+	}
+
+	for _, c := range caches {
+		test.WaitForServer(t, c)
+	}
+
+	ctx := context.Background()
+	values := map[string]string{"1": "value-1", "2": "value-2", "3": "value-3"}
+	if err := tables[0].PutMany(ctx, values, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, table := range tables {
+		got, err := table.GetMany(ctx, []string{"1", "2", "3", "4"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, values) {
+			t.Fatalf("expected: %v\ngot: %v", values, got)
+		}
+	}
+
+	test.TearDown(t, caches[2])
+
+	got, err := tables[0].GetMany(ctx, []string{"1", "2", "3"})
+	errs, ok := err.(nitecache.BatchGetErrs)
+	if !ok {
+		t.Fatalf("expected BatchGetErrs, got: %v", err)
+	}
+	if affected := errs.AffectedKeys(); !reflect.DeepEqual(affected, []string{"3"}) {
+		t.Fatalf("expected affected keys: %v\ngot: %v", []string{"3"}, affected)
+	}
+
+	expected := map[string]string{"1": "value-1", "2": "value-2"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected: %v\ngot: %v", expected, got)
+	}
+
+	err = tables[0].PutMany(ctx, values, time.Hour)
+	if _, ok := err.(nitecache.BatchPutErrs); !ok {
+		t.Fatalf("expected BatchPutErrs, got: %v", err)
+	}
+
+	for _, i := range []int{0, 1} {
+		test.TearDown(t, caches[i])
+	}
+}
+
+func TestBatchGetErrs(t *testing.T) {
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+
+	errGetter := errors.New("getter failed")
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[string], len(members))
+	for i, m := range members {
+		c, err := nitecache.NewCache(m, members,
+			nitecache.VirtualNodeOpt(1),
+			nitecache.HashFuncOpt(test.SimpleHashFunc),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+				return
+			}
+		}()
+
+		caches[i] = c
+		tables[i] = nitecache.NewTable[string]("test").
+			WithGetter(func(_ context.Context, key string) (string, time.Duration, error) {
+				if key == "3" {
+					return "", 0, errGetter
+				}
+				return "value-" + key, time.Hour, nil
+			}).
+			Build(c)
+	}
+
+	for _, c := range caches {
+		test.WaitForServer(t, c)
+	}
+
+	// A failing key must not affect the other keys of its batch, whether it is owned locally or by a peer
+	ctx := context.Background()
+	expected := map[string]string{"1": "value-1", "2": "value-2", "4": "value-4"}
+	for _, table := range tables {
+		got, err := table.GetMany(ctx, []string{"1", "2", "3", "4"})
+		errs, ok := err.(nitecache.BatchGetErrs)
+		if !ok {
+			t.Fatalf("expected BatchGetErrs, got: %v", err)
+		}
+		if affected := errs.AffectedKeys(); !reflect.DeepEqual(affected, []string{"3"}) {
+			t.Fatalf("expected affected keys: %v\ngot: %v", []string{"3"}, affected)
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected: %v\ngot: %v", expected, got)
+		}
+	}
+
+	for _, c := range caches {
+		test.TearDown(t, c)
+	}
+}
+
+func TestCompareAndSwapCacheTable(t *testing.T) {
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[string], len(members))
+	for i, m := range members {
+		c, err := nitecache.NewCache(m, members,
+			nitecache.VirtualNodeOpt(1),
+			nitecache.HashFuncOpt(test.SimpleHashFunc),
+			nitecache.ReplicationFactorOpt(2),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+				return
+			}
+		}()
+
+		caches[i] = c
+		tables[i] = nitecache.NewTable[string]("test").Build(c)
+	}
+
+	for _, c := range caches {
+		test.WaitForServer(t, c)
+	}
+
+	ctx := context.Background()
+	for _, key := range []string{"1", "2"} {
+		version, err := tables[0].CompareAndSwap(ctx, key, 0, "first", time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tables[1].CompareAndSwap(ctx, key, 0, "conflict", time.Hour); !errors.Is(err, nitecache.ErrVersionMismatch) {
+			t.Fatalf("expected error %v, got: %v", nitecache.ErrVersionMismatch, err)
+		}
+
+		for _, table := range tables {
+			v, gotVersion, err := table.GetWithVersion(ctx, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v != "first" || gotVersion != version {
+				t.Fatalf("expected value %q with version %d, got: %q with version %d", "first", version, v, gotVersion)
+			}
+		}
+
+		newVersion, err := tables[1].CompareAndSwap(ctx, key, version, "second", time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if newVersion <= version {
+			t.Fatalf("expected version greater than %d, got: %d", version, newVersion)
+		}
+
+		// Swapped values must be copied to the replicas
+		for _, table := range tables {
+			if v, err := table.GetHot(key); err != nil || v != "second" {
+				t.Fatalf("expected value %q, got: %q, err: %v", "second", v, err)
+			}
+		}
+
+		if err := tables[0].Put(ctx, key, "third", time.Hour); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tables[0].CompareAndSwap(ctx, key, newVersion, "fourth", time.Hour); !errors.Is(err, nitecache.ErrVersionMismatch) {
+			t.Fatalf("expected error %v after Put, got: %v", nitecache.ErrVersionMismatch, err)
+		}
+	}
+
+	for _, c := range caches {
+		test.TearDown(t, c)
+	}
+}
+
+func TestStaleWhileRevalidateCacheTable(t *testing.T) {
+	ctx := context.Background()
+	self := nitecache.Member{
+		ID:   "1",
+		Addr: test.GetUniqueAddr(),
+	}
+
+	c, err := nitecache.NewCache(self, []nitecache.Member{self})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDown(t, c)
+
+	refreshed := make(chan struct{})
+	var calls int
+	table := nitecache.NewTable[string]("test").
+		WithGetter(func(_ context.Context, _ string) (string, time.Duration, error) {
+			calls++
+			if calls > 1 {
+				defer close(refreshed)
+				return "fresh", time.Hour, nil
+			}
+			return "stale", time.Millisecond * 10, nil
+		}).
+		WithStaleWhileRevalidate(time.Hour).
+		Build(c)
+
+	if v, err := table.Get(ctx, "1"); err != nil || v != "stale" {
+		t.Fatalf("expected value %q, got: %q, err: %v", "stale", v, err)
+	}
+	time.Sleep(time.Millisecond * 20)
+
+	if v, err := table.Get(ctx, "1"); err != nil || v != "stale" {
+		t.Fatalf("expected expired value %q, got: %q, err: %v", "stale", v, err)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("expected value to be refreshed in the background")
+	}
+
+	// The refreshed value is stored right after the getter returns
+	time.Sleep(time.Millisecond * 10)
+	if v, err := table.Get(ctx, "1"); err != nil || v != "fresh" {
+		t.Fatalf("expected value %q, got: %q, err: %v", "fresh", v, err)
+	}
+}
+
+func TestNegativeTTLCacheTable(t *testing.T) {
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+
+	var calls atomic.Int64
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[string], len(members))
+	for i, m := range members {
+		c, err := nitecache.NewCache(m, members,
+			nitecache.VirtualNodeOpt(1),
+			nitecache.HashFuncOpt(test.SimpleHashFunc),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer test.TearDown(t, c)
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+				return
+			}
+		}()
+
+		caches[i] = c
+		tables[i] = nitecache.NewTable[string]("test").
+			WithGetter(func(_ context.Context, key string) (string, time.Duration, error) {
+				calls.Add(1)
+				return "", 0, fmt.Errorf("user %s: %w", key, nitecache.ErrNotExist)
+			}).
+			WithNegativeTTL(time.Hour).
+			Build(c)
+	}
+
+	for _, c := range caches {
+		test.WaitForServer(t, c)
+	}
+
+	// Key "2" is owned by member "2", the absence must be cached there for both members
+	ctx := context.Background()
+	for _, table := range tables {
+		for i := 0; i < 2; i++ {
+			if _, err := table.Get(ctx, "2"); !errors.Is(err, nitecache.ErrKeyNotFound) {
+				t.Fatalf("expected error %v, got: %v", nitecache.ErrKeyNotFound, err)
+			}
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected getter to be called once, got: %d", n)
+	}
+
+	if err := tables[0].Put(ctx, "2", "created", 0); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := tables[0].Get(ctx, "2"); err != nil || v != "created" {
+		t.Fatalf("expected value %q, got: %q, err: %v", "created", v, err)
+	}
+}
+
+func TestOnEvictCacheTable(t *testing.T) {
+	ctx := context.Background()
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+	opts := []nitecache.CacheOpt{
+		nitecache.VirtualNodeOpt(1),
+		nitecache.HashFuncOpt(test.SimpleHashFunc),
+	}
+
+	c1, err := nitecache.NewCache(members[0], members[:1], opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		if err := c1.ListenAndServe(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	var mu sync.Mutex
+	evicted := map[string]nitecache.EvictReason{}
+	table1 := nitecache.NewTable[string]("test").
+		WithStorage(nitecache.LRU(2)).
+		WithOnEvict(func(key string, value string, reason nitecache.EvictReason) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if value != "value-"+key {
+				t.Errorf("expected value %q, got: %q", "value-"+key, value)
+			}
+			evicted[key] = reason
+		}).
+		Build(c1)
+
+	for _, key := range []string{"1", "2", "3"} {
+		if err := table1.Put(ctx, key, "value-"+key, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := table1.Evict(ctx, "3"); err != nil {
+		t.Fatal(err)
+	}
+
+	c2, err := nitecache.NewCache(members[1], members, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDown(t, c2)
+	go func() {
+		if err := c2.ListenAndServe(); err != nil {
+			t.Error(err)
+		}
+	}()
+	nitecache.NewTable[string]("test").Build(c2)
+	test.WaitForServer(t, c2)
+
+	// Key "2" is now owned by member "2"
+	if err := c1.SetPeers(members); err != nil {
+		t.Fatal(err)
+	}
+	test.TearDown(t, c1)
+
+	expected := map[string]nitecache.EvictReason{
+		"1": nitecache.EvictCapacity,
+		"2": nitecache.EvictRebalanced,
+		"3": nitecache.EvictExplicit,
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(evicted, expected) {
+		t.Fatalf("expected evictions %v\ngot %v", expected, evicted)
+	}
+}
+
+func TestCache_PlacementOpt(t *testing.T) {
+	strategies := map[string]nitecache.PlacementStrategy{
+		"ring":       nitecache.RingPlacement,
+		"rendezvous": nitecache.RendezvousPlacement,
+		"jump":       nitecache.JumpPlacement,
+	}
+
+	for name, strategy := range strategies {
+		t.Run(name, func(t *testing.T) {
+			members := []nitecache.Member{
+				{
+					ID:   "1",
+					Addr: test.GetUniqueAddr(),
+				}, {
+					ID:   "2",
+					Addr: test.GetUniqueAddr(),
+				},
+			}
+
+			caches := make([]*nitecache.Cache, len(members))
+			tables := make([]*nitecache.Table[string], len(members))
+			for i, m := range members {
+				c, err := nitecache.NewCache(m, members, nitecache.PlacementOpt(strategy))
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer test.TearDown(t, c)
+
+				go func() {
+					if err := c.ListenAndServe(); err != nil {
+						t.Error(err)
+					}
+				}()
+
+				caches[i] = c
+				tables[i] = nitecache.NewTable[string]("test").Build(c)
+			}
+			for _, c := range caches {
+				test.WaitForServer(t, c)
+			}
+
+			ctx := context.Background()
+			for i := 0; i < 10; i++ {
+				key := strconv.Itoa(i)
+				if err := tables[i%2].Put(ctx, key, "value-"+key, 0); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for i := 0; i < 10; i++ {
+				key := strconv.Itoa(i)
+				if v, err := tables[(i+1)%2].Get(ctx, key); err != nil || v != "value-"+key {
+					t.Fatalf("expected value %q for key %s, got: %q, err: %v", "value-"+key, key, v, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCache_BoundedLoadsOpt(t *testing.T) {
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+
+	ring, err := hashring.New(hashring.Opt{
+		Members:      []string{"1", "2"},
+		VirtualNodes: 1,
+		HashFunc:     test.SimpleHashFunc,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for i := 0; len(keys) < 6; i++ {
+		key := strconv.Itoa(i)
+		if owner, err := ring.GetOwner(key); err == nil && owner == "1" {
+			keys = append(keys, key)
+		}
+	}
+
+	started := make(chan string)
+	release := make(chan struct{})
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[string], len(members))
+	for i, m := range members {
+		c, err := nitecache.NewCache(m, members,
+			nitecache.VirtualNodeOpt(1),
+			nitecache.HashFuncOpt(test.SimpleHashFunc),
+			nitecache.BoundedLoadsOpt(0.25),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer test.TearDown(t, c)
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		id := m.ID
+		caches[i] = c
+		tables[i] = nitecache.NewTable[string]("test").
+			WithGetter(func(_ context.Context, key string) (string, time.Duration, error) {
+				// Keep the request in flight, identifying which member fills the value
+				started <- id
+				<-release
+				return id, 0, nil
+			}).
+			Build(c)
+	}
+	for _, c := range caches {
+		test.WaitForServer(t, c)
+	}
+
+	// Every key is owned by member 1, but requests are sent to member 2 once member 1 is over capacity
+	ctx := context.Background()
+	errs := make(chan error, len(keys))
+	filledBy := map[string]string{}
+	for _, key := range keys {
+		go func(key string) {
+			_, err := tables[1].Get(ctx, key)
+			errs <- err
+		}(key)
+		filledBy[key] = <-started
+	}
+
+	expected := map[string]string{
+		keys[0]: "1",
+		keys[1]: "1",
+		// Capacity is ceil(1.25 * 3 / 2) = 2
+		keys[2]: "2",
+		keys[3]: "1",
+		keys[4]: "1",
+		// Capacity is ceil(1.25 * 6 / 2) = 4
+		keys[5]: "2",
+	}
+	close(release)
+	for range keys {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(filledBy, expected) {
+		t.Fatalf("expected values to be filled by %v\ngot %v", expected, filledBy)
+	}
+
+	// Values filled by member 2 must be dropped once written on their owner
+	for _, key := range []string{keys[2], keys[5]} {
+		if err := tables[0].Put(ctx, key, "updated", time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, "values filled by member 2 to be dropped", func() bool {
+		metrics, err := caches[1].GetTableMetrics()
+		return err == nil && metrics["test"].Items == 0
+	})
+}
+
+func TestCache_Zones(t *testing.T) {
+	members := []nitecache.Member{
+		{ID: "1", Addr: test.GetUniqueAddr(), Zone: "a"},
+		{ID: "2", Addr: test.GetUniqueAddr(), Zone: "a"},
+		{ID: "3", Addr: test.GetUniqueAddr(), Zone: "b"},
+		{ID: "4", Addr: test.GetUniqueAddr(), Zone: "b"},
+	}
+
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[string], len(members))
+	for i, m := range members {
+		c, err := nitecache.NewCache(m, members, nitecache.ReplicationFactorOpt(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer test.TearDown(t, c)
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		caches[i] = c
+		tables[i] = nitecache.NewTable[string]("test").Build(c)
+	}
+	for _, c := range caches {
+		test.WaitForServer(t, c)
+	}
+
+	const keys = 20
+	ctx := context.Background()
+	for i := 0; i < keys; i++ {
+		key := strconv.Itoa(i)
+		if err := tables[0].Put(ctx, key, "value-"+key, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Every key has a replica in each zone, so gets are served within the zone of the caller
+	for _, table := range []*nitecache.Table[string]{tables[0], tables[2]} {
+		for i := 0; i < keys; i++ {
+			key := strconv.Itoa(i)
+			if v, err := table.Get(ctx, key); err != nil || v != "value-"+key {
+				t.Fatalf("expected value %q for key %s, got: %q, err: %v", "value-"+key, key, v, err)
+			}
+		}
+	}
+
+	gets := map[string]int64{}
+	for i, table := range tables {
+		m, err := table.GetMetrics()
+		if err != nil {
+			t.Fatal(err)
+		}
+		gets[members[i].Zone] += m.Get
+	}
+	if expected := map[string]int64{"a": keys, "b": keys}; !reflect.DeepEqual(gets, expected) {
+		t.Fatalf("expected gets per zone %v\ngot %v", expected, gets)
+	}
+}
+
+func TestCache_ZonesReplicaMiss(t *testing.T) {
+	ctx := context.Background()
+	members := []nitecache.Member{
+		{ID: "1", Addr: test.GetUniqueAddr(), Zone: "a"},
+		{ID: "2", Addr: test.GetUniqueAddr(), Zone: "b"},
+	}
+	opts := []nitecache.CacheOpt{
+		nitecache.VirtualNodeOpt(1),
+		nitecache.HashFuncOpt(test.SimpleHashFunc),
+		nitecache.ReplicationFactorOpt(2),
+	}
+
+	// Member "2" stores the key before member "1" joins, so only the primary holds it
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[string], len(members))
+	for _, i := range []int{1, 0} {
+		c, err := nitecache.NewCache(members[i], members[i:], opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer test.TearDown(t, c)
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		caches[i] = c
+		tables[i] = nitecache.NewTable[string]("test").Build(c)
+		test.WaitForServer(t, c)
+
+		// Key "2" is owned by member "2" and replicated on member "1"
+		if i == 1 {
+			if err := tables[1].Put(ctx, "2", "value", time.Hour); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Member "1" is the zone-local replica, but must fall back to the primary
+	if v, err := tables[0].Get(ctx, "2"); err != nil || v != "value" {
+		t.Fatalf("expected value %q, got: %q, err: %v", "value", v, err)
+	}
+
+	got, err := tables[0].GetMany(ctx, []string{"2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]string{"2": "value"}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected: %v\ngot: %v", expected, got)
+	}
+}
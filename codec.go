@@ -78,3 +78,15 @@ func (c GobCodec[T]) Encode(v T) ([]byte, error) {
 	err := gob.NewEncoder(&buf).Encode(v)
 	return buf.Bytes(), err
 }
+
+// Returns [StringCodec] for string and []byte types, [JsonCodec] otherwise
+func defaultCodec[T any]() Codec[T] {
+	var v T
+	anyV := any(v)
+	if _, isByteSlice := anyV.([]byte); isByteSlice {
+		return any(StringCodec[[]byte]{}).(Codec[T])
+	} else if _, isString := anyV.(string); isString {
+		return any(StringCodec[string]{}).(Codec[T])
+	}
+	return &JsonCodec[T]{}
+}
@@ -0,0 +1,278 @@
+package nitecache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Discovery defines the interface used to keep the cache members up to date.
+	//
+	// Refer to [DiscoveryOpt] for using a Discovery with a [Cache].
+	//
+	// Watch must call setPeers every time the members change and return once ctx is done.
+	Discovery interface {
+		Watch(ctx context.Context, setPeers func(peers []Member) error)
+	}
+	// Resolver defines the DNS lookups required by [DNSDiscovery].
+	//
+	// [net.Resolver] implements this interface.
+	Resolver interface {
+		LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+		LookupHost(ctx context.Context, host string) ([]string, error)
+	}
+	// DNSDiscoveryOpt configures a [DNSDiscovery].
+	DNSDiscoveryOpt struct {
+		// Name of the record to look up.
+		Name string
+		// Service and Proto are used to look up SRV records (i.e. _service._proto.name).
+		//
+		// If both are empty, A/AAAA records are looked up instead.
+		Service string
+		Proto   string
+		// Port is appended to the resolved addresses when looking up A/AAAA records.
+		Port int
+		// Defaults to [net.DefaultResolver]
+		Resolver Resolver
+		// Defaults to 30 seconds
+		Interval time.Duration
+		// Defaults to 5 seconds
+		Debounce time.Duration
+	}
+	// DNSDiscovery implements [Discovery] by periodically resolving DNS SRV or A/AAAA records.
+	//
+	// Members are identified by their normalised address (see [ErrDiscoveryID]),
+	// so the ID of the current node must be its own address, as resolved by other members.
+	DNSDiscovery struct {
+		opt DNSDiscoveryOpt
+	}
+	// FileDiscoveryOpt configures a [FileDiscovery].
+	FileDiscoveryOpt struct {
+		// Path of a JSON or YAML file containing a list of members.
+		//
+		// The format is determined using the file extension (.json, .yaml or .yml).
+		Path string
+		// Defaults to 5 seconds
+		Interval time.Duration
+		// Defaults to 5 seconds
+		Debounce time.Duration
+	}
+	// FileDiscovery implements [Discovery] by watching a file containing the members.
+	//
+	// The current node is identified by its ID, so it must be listed with the same ID on every member.
+	//
+	// Ex.:
+	//
+	//	- id: "1"
+	//	  addr: node1:8100
+	//	- id: "2"
+	//	  addr: node2:8100
+	FileDiscovery struct {
+		opt FileDiscoveryOpt
+	}
+	// debouncer only applies changes once they have been stable for a given delay.
+	//
+	// The first members are applied right away, so that the cache doesn't wait for a full interval after starting.
+	debouncer struct {
+		delay        time.Duration
+		setPeers     func(peers []Member) error
+		current      []Member
+		pending      []Member
+		pendingSince time.Time
+	}
+)
+
+// DiscoveryOpt sets the [Discovery] used to update the cache members.
+//
+// Discovery stops when calling [Cache.TearDown].
+func DiscoveryOpt(discovery Discovery) func(c *Cache) {
+	return func(c *Cache) {
+		c.discovery = discovery
+	}
+}
+
+// NewDNSDiscovery creates a new [DNSDiscovery].
+func NewDNSDiscovery(opt DNSDiscoveryOpt) *DNSDiscovery {
+	if opt.Resolver == nil {
+		opt.Resolver = net.DefaultResolver
+	}
+	if opt.Interval == 0 {
+		opt.Interval = time.Second * 30
+	}
+	if opt.Debounce == 0 {
+		opt.Debounce = time.Second * 5
+	}
+	return &DNSDiscovery{opt: opt}
+}
+
+func (d *DNSDiscovery) Watch(ctx context.Context, setPeers func(peers []Member) error) {
+	watch(ctx, d.opt.Interval, d.opt.Debounce, d.lookup, setPeers)
+}
+
+func (d *DNSDiscovery) lookup(ctx context.Context) ([]Member, error) {
+	var addrs []string
+	if d.opt.Service == "" && d.opt.Proto == "" {
+		hosts, err := d.opt.Resolver.LookupHost(ctx, d.opt.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, host := range hosts {
+			addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(d.opt.Port)))
+		}
+	} else {
+		_, records, err := d.opt.Resolver.LookupSRV(ctx, d.opt.Service, d.opt.Proto, d.opt.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			addrs = append(addrs, net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port))))
+		}
+	}
+
+	seen := map[string]bool{}
+	members := make([]Member, 0, len(addrs))
+	for _, addr := range addrs {
+		addr = normalizeAddr(addr)
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		members = append(members, Member{ID: addr, Addr: addr})
+	}
+	return members, nil
+}
+
+// NewFileDiscovery creates a new [FileDiscovery].
+func NewFileDiscovery(opt FileDiscoveryOpt) *FileDiscovery {
+	if opt.Interval == 0 {
+		opt.Interval = time.Second * 5
+	}
+	if opt.Debounce == 0 {
+		opt.Debounce = time.Second * 5
+	}
+	return &FileDiscovery{opt: opt}
+}
+
+func (d *FileDiscovery) Watch(ctx context.Context, setPeers func(peers []Member) error) {
+	var modTime time.Time
+	var members []Member
+	watch(ctx, d.opt.Interval, d.opt.Debounce, func(_ context.Context) ([]Member, error) {
+		info, err := os.Stat(d.opt.Path)
+		if err != nil {
+			return nil, err
+		}
+		// Avoid parsing the file again if it wasn't modified
+		if info.ModTime().Equal(modTime) {
+			return members, nil
+		}
+
+		newMembers, err := d.read()
+		if err != nil {
+			return nil, err
+		}
+
+		modTime, members = info.ModTime(), newMembers
+		return members, nil
+	}, setPeers)
+}
+
+func (d *FileDiscovery) read() ([]Member, error) {
+	b, err := os.ReadFile(d.opt.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []Member
+	switch ext := filepath.Ext(d.opt.Path); ext {
+	case ".json":
+		err = json.Unmarshal(b, &members)
+	case ".yaml", ".yml":
+		err = yaml.NewDecoder(bytes.NewReader(b)).Decode(&members)
+	default:
+		err = fmt.Errorf("unsupported file extension %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read members from %s: %w", d.opt.Path, err)
+	}
+
+	return members, nil
+}
+
+// Call lookup every interval and pass the result through a debouncer until ctx is done.
+//
+// Lookup errors are ignored, so that a temporary failure doesn't remove every member.
+func watch(
+	ctx context.Context,
+	interval, delay time.Duration,
+	lookup func(ctx context.Context) ([]Member, error),
+	setPeers func(peers []Member) error,
+) {
+	d := &debouncer{delay: delay, setPeers: setPeers}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if members, err := lookup(ctx); err == nil && len(members) > 0 {
+			d.update(members, time.Now())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *debouncer) update(members []Member, now time.Time) {
+	members = sortMembers(members)
+	if !slices.Equal(members, d.pending) {
+		d.pending = members
+		d.pendingSince = now
+	}
+
+	if slices.Equal(d.pending, d.current) || d.current != nil && now.Sub(d.pendingSince) < d.delay {
+		return
+	}
+
+	// On error, the update will be retried on the next call
+	if err := d.setPeers(d.pending); err == nil {
+		d.current = d.pending
+	}
+}
+
+func sortMembers(members []Member) []Member {
+	sorted := slices.Clone(members)
+	slices.SortFunc(sorted, func(a, b Member) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return sorted
+}
+
+// Normalise the host of addr, so that equivalent addresses can be compared.
+//
+// Hosts are lowercased and stripped of their trailing dot, IPs are formatted in their canonical form.
+func normalizeAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if ip := net.ParseIP(host); ip != nil {
+		host = ip.String()
+	}
+	return net.JoinHostPort(host, port)
+}
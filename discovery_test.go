@@ -0,0 +1,295 @@
+package nitecache_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MysteriousPotato/nitecache"
+	test "github.com/MysteriousPotato/nitecache/test_utils"
+)
+
+type fakeResolver struct {
+	mu      sync.Mutex
+	records []*net.SRV
+	hosts   []string
+}
+
+func (r *fakeResolver) LookupSRV(_ context.Context, _, _, _ string) (string, []*net.SRV, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return "", r.records, nil
+}
+
+func (r *fakeResolver) LookupHost(_ context.Context, _ string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.hosts, nil
+}
+
+func (r *fakeResolver) setRecords(targets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = nil
+	for _, target := range targets {
+		r.records = append(r.records, &net.SRV{Target: target + ".", Port: 8000})
+	}
+}
+
+func watchDiscovery(d nitecache.Discovery) (chan []nitecache.Member, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan []nitecache.Member, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.Watch(ctx, func(peers []nitecache.Member) error {
+			ch <- peers
+			return nil
+		})
+	}()
+
+	return ch, func() {
+		cancel()
+		<-done
+	}
+}
+
+func assertPeers(t *testing.T, ch chan []nitecache.Member, expected []nitecache.Member) {
+	select {
+	case got := <-ch:
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected peers: %v\ngot: %v", expected, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected peers: %v\ngot nothing", expected)
+	}
+}
+
+func TestDNSDiscovery(t *testing.T) {
+	resolver := &fakeResolver{}
+	resolver.setRecords("node-1", "node-2")
+
+	ch, stop := watchDiscovery(nitecache.NewDNSDiscovery(nitecache.DNSDiscoveryOpt{
+		Name:     "nitecache",
+		Service:  "cache",
+		Proto:    "tcp",
+		Resolver: resolver,
+		Interval: time.Millisecond * 5,
+		Debounce: time.Millisecond * 50,
+	}))
+	defer stop()
+
+	assertPeers(t, ch, []nitecache.Member{
+		{ID: "node-1:8000", Addr: "node-1:8000"},
+		{ID: "node-2:8000", Addr: "node-2:8000"},
+	})
+
+	// Flapping updates shorter than the debounce delay must be ignored
+	resolver.setRecords("node-1")
+	time.Sleep(time.Millisecond * 10)
+	resolver.setRecords("node-2", "node-1")
+	time.Sleep(time.Millisecond * 100)
+
+	select {
+	case got := <-ch:
+		t.Fatalf("expected no update, got: %v", got)
+	default:
+	}
+
+	resolver.setRecords("node-3")
+	assertPeers(t, ch, []nitecache.Member{{ID: "node-3:8000", Addr: "node-3:8000"}})
+}
+
+func TestDNSDiscovery_Host(t *testing.T) {
+	resolver := &fakeResolver{hosts: []string{"10.0.0.2", "10.0.0.1"}}
+
+	ch, stop := watchDiscovery(nitecache.NewDNSDiscovery(nitecache.DNSDiscoveryOpt{
+		Name:     "nitecache",
+		Port:     8100,
+		Resolver: resolver,
+		Interval: time.Millisecond * 5,
+		Debounce: time.Millisecond * 5,
+	}))
+	defer stop()
+
+	assertPeers(t, ch, []nitecache.Member{
+		{ID: "10.0.0.1:8100", Addr: "10.0.0.1:8100"},
+		{ID: "10.0.0.2:8100", Addr: "10.0.0.2:8100"},
+	})
+}
+
+func TestDNSDiscovery_FirstResolution(t *testing.T) {
+	resolver := &fakeResolver{hosts: []string{"10.0.0.1"}}
+
+	ch, stop := watchDiscovery(nitecache.NewDNSDiscovery(nitecache.DNSDiscoveryOpt{
+		Name:     "nitecache",
+		Port:     8100,
+		Resolver: resolver,
+		Interval: time.Hour,
+		Debounce: time.Hour,
+	}))
+	defer stop()
+
+	assertPeers(t, ch, []nitecache.Member{{ID: "10.0.0.1:8100", Addr: "10.0.0.1:8100"}})
+}
+
+func TestFileDiscovery(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected []nitecache.Member
+	}{{
+		name:     "json",
+		file:     "members.json",
+		content:  `[{"id": "2", "addr": "node2:8100"}, {"id": "1", "addr": "node1:8100"}]`,
+		expected: []nitecache.Member{{ID: "1", Addr: "node1:8100"}, {ID: "2", Addr: "node2:8100"}},
+	}, {
+		name:     "yaml",
+		file:     "members.yaml",
+		content:  "- id: \"1\"\n  addr: node1:8100\n- id: \"3\"\n  addr: node3:8100\n",
+		expected: []nitecache.Member{{ID: "1", Addr: "node1:8100"}, {ID: "3", Addr: "node3:8100"}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte("[]"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			ch, stop := watchDiscovery(nitecache.NewFileDiscovery(nitecache.FileDiscoveryOpt{
+				Path:     path,
+				Interval: time.Millisecond * 5,
+				Debounce: time.Millisecond * 5,
+			}))
+			defer stop()
+
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			assertPeers(t, ch, tt.expected)
+		})
+	}
+}
+
+func TestCache_DiscoveryOpt(t *testing.T) {
+	addrs := []string{test.GetUniqueAddr(), test.GetUniqueAddr()}
+
+	path := filepath.Join(t.TempDir(), "members.json")
+	content := `[{"id": "1", "addr": "` + addrs[0] + `"}, {"id": "2", "addr": "` + addrs[1] + `"}]`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	members := []nitecache.Member{{ID: "1", Addr: addrs[0]}, {ID: "2", Addr: addrs[1]}}
+	assertSameOwners(t, members, func() nitecache.Discovery {
+		return nitecache.NewFileDiscovery(nitecache.FileDiscoveryOpt{
+			Path:     path,
+			Interval: time.Millisecond * 5,
+			Debounce: time.Millisecond * 5,
+		})
+	})
+}
+
+func TestCache_DNSDiscoveryOpt(t *testing.T) {
+	addrs := []string{test.GetUniqueAddr(), test.GetUniqueAddr()}
+
+	resolver := &fakeResolver{}
+	for _, addr := range addrs {
+		host, port, _ := net.SplitHostPort(addr)
+		p, _ := strconv.Atoi(port)
+		resolver.records = append(resolver.records, &net.SRV{Target: host + ".", Port: uint16(p)})
+	}
+
+	newDiscovery := func() nitecache.Discovery {
+		return nitecache.NewDNSDiscovery(nitecache.DNSDiscoveryOpt{
+			Name:     "nitecache",
+			Service:  "cache",
+			Proto:    "tcp",
+			Resolver: resolver,
+			Interval: time.Millisecond * 5,
+			Debounce: time.Millisecond * 5,
+		})
+	}
+
+	// Other members identify self by its address
+	_, err := nitecache.NewCache(nitecache.Member{ID: "1", Addr: addrs[0]}, nil, nitecache.DiscoveryOpt(newDiscovery()))
+	if !errors.Is(err, nitecache.ErrDiscoveryID) {
+		t.Fatalf("expected error %v, got: %v", nitecache.ErrDiscoveryID, err)
+	}
+
+	members := []nitecache.Member{{ID: addrs[0], Addr: addrs[0]}, {ID: addrs[1], Addr: addrs[1]}}
+	assertSameOwners(t, members, newDiscovery)
+}
+
+// Start a cache for every member using discovery, and make sure every member agrees on the owner of keys
+func assertSameOwners(t *testing.T, members []nitecache.Member, newDiscovery func() nitecache.Discovery) {
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[string], len(members))
+	for i, m := range members {
+		c, err := nitecache.NewCache(m, nil, nitecache.DiscoveryOpt(newDiscovery()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer test.TearDown(t, c)
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		caches[i] = c
+		tables[i] = nitecache.NewTable[string]("test").Build(c)
+	}
+	for _, c := range caches {
+		test.WaitForServer(t, c)
+	}
+
+	expected := make([]string, len(members))
+	for i, m := range members {
+		expected[i] = m.ID
+	}
+	slices.Sort(expected)
+
+	for _, c := range caches {
+		var got []string
+		for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond * 5) {
+			var err error
+			if got, err = c.GetRingMembers(); err != nil {
+				t.Fatal(err)
+			}
+			slices.Sort(got)
+			if slices.Equal(got, expected) {
+				break
+			}
+		}
+		if !slices.Equal(got, expected) {
+			t.Fatalf("expected members: %v\ngot: %v", expected, got)
+		}
+	}
+
+	// Tables have no getter, so a value is only found if both members route the key to the same owner
+	ctx := context.Background()
+	for i := 0; i < 20; i++ {
+		key := strconv.Itoa(i)
+		if err := tables[0].Put(ctx, key, "value-"+key, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+		if v, err := tables[1].Get(ctx, key); err != nil || v != "value-"+key {
+			t.Fatalf("expected value %q for key %s, got: %q, err: %v", "value-"+key, key, v, err)
+		}
+	}
+}
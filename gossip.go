@@ -0,0 +1,400 @@
+package nitecache
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/MysteriousPotato/nitecache/servicepb"
+)
+
+type (
+	// GossipConfig configures the SWIM-style failure detection enabled through [GossipOpt].
+	GossipConfig struct {
+		// Delay between each probe.
+		//
+		// Defaults to 1 second
+		Interval time.Duration
+		// Timeout for direct and indirect pings.
+		//
+		// Defaults to 500 milliseconds
+		PingTimeout time.Duration
+		// Delay before a suspect member is declared dead.
+		//
+		// Defaults to 5 seconds
+		SuspicionTimeout time.Duration
+		// Number of members asked to ping a member that failed to respond to a direct ping.
+		//
+		// Defaults to 3
+		IndirectChecks int
+		// Multiplier used to determine how many times a membership update is piggybacked on pings (i.e. RetransmitMult * log(n+1)).
+		//
+		// Defaults to 4
+		RetransmitMult int
+	}
+	gossip struct {
+		cfg         GossipConfig
+		cache       *Cache
+		mu          *sync.Mutex
+		incarnation uint64
+		states      map[string]*memberState
+		broadcasts  []*broadcast
+		probeOrder  []string
+		probeIndex  int
+		ringDirty   atomic.Bool
+	}
+	memberState struct {
+		status       servicepb.MemberStatus
+		incarnation  uint64
+		suspectSince time.Time
+	}
+	broadcast struct {
+		state     *servicepb.MemberState
+		transmits int
+	}
+)
+
+var errGossipDisabled = errors.New("gossip not enabled")
+
+// GossipOpt enables a SWIM-style gossip protocol between members.
+//
+// Members that fail to respond to direct and indirect pings are marked as suspect, then as dead once [GossipConfig.SuspicionTimeout] expires.
+// Dead members are removed from the hashring until they recover, without requiring a call to [Cache.SetPeers].
+//
+// Gossip stops when calling [Cache.TearDown].
+func GossipOpt(cfg GossipConfig) func(c *Cache) {
+	return func(c *Cache) {
+		if cfg.Interval == 0 {
+			cfg.Interval = time.Second
+		}
+		if cfg.PingTimeout == 0 {
+			cfg.PingTimeout = time.Millisecond * 500
+		}
+		if cfg.SuspicionTimeout == 0 {
+			cfg.SuspicionTimeout = time.Second * 5
+		}
+		if cfg.IndirectChecks == 0 {
+			cfg.IndirectChecks = 3
+		}
+		if cfg.RetransmitMult == 0 {
+			cfg.RetransmitMult = 4
+		}
+
+		c.gossip = &gossip{
+			cfg:    cfg,
+			cache:  c,
+			mu:     &sync.Mutex{},
+			states: map[string]*memberState{},
+		}
+	}
+}
+
+// Add new members as alive and forget about removed members
+func (g *gossip) setMembers(ids []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	idsMap := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		idsMap[id] = struct{}{}
+		if _, ok := g.states[id]; !ok && id != g.cache.self.ID {
+			g.states[id] = &memberState{status: servicepb.MemberStatus_MEMBER_STATUS_ALIVE}
+		}
+	}
+	for id := range g.states {
+		if _, ok := idsMap[id]; !ok {
+			delete(g.states, id)
+		}
+	}
+}
+
+func (g *gossip) isDead(id string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state, ok := g.states[id]
+	return ok && state.status == servicepb.MemberStatus_MEMBER_STATUS_DEAD
+}
+
+func (g *gossip) run(ctx context.Context) {
+	ticker := time.NewTicker(g.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if target, ok := g.nextTarget(); ok {
+			g.probe(ctx, target)
+		}
+		g.expireSuspects()
+
+		// The hashring is only updated from here to avoid blocking ping handlers while rebalancing
+		if g.ringDirty.CompareAndSwap(true, false) {
+			_ = g.cache.updateMembers()
+		}
+	}
+}
+
+// Pick members in a randomized round-robin fashion, so that every member is probed within a bounded time
+func (g *gossip) nextTarget() (string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i := 0; i < 2; i++ {
+		for ; g.probeIndex < len(g.probeOrder); g.probeIndex++ {
+			if _, ok := g.states[g.probeOrder[g.probeIndex]]; ok {
+				g.probeIndex++
+				return g.probeOrder[g.probeIndex-1], true
+			}
+		}
+
+		g.probeOrder = g.probeOrder[:0]
+		for id := range g.states {
+			g.probeOrder = append(g.probeOrder, id)
+		}
+		rand.Shuffle(len(g.probeOrder), func(i, j int) {
+			g.probeOrder[i], g.probeOrder[j] = g.probeOrder[j], g.probeOrder[i]
+		})
+		g.probeIndex = 0
+	}
+	return "", false
+}
+
+func (g *gossip) probe(ctx context.Context, target string) {
+	res, err := g.ping(ctx, target)
+	if err != nil {
+		res, err = g.indirectPing(ctx, target)
+	}
+	if err != nil {
+		g.apply(g.suspect(target))
+		return
+	}
+
+	g.apply(res.Updates...)
+}
+
+func (g *gossip) ping(ctx context.Context, target string) (*servicepb.PingResponse, error) {
+	client, err := g.cache.getClient(target)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, g.cfg.PingTimeout)
+	defer cancel()
+
+	return client.Ping(ctx, &servicepb.PingRequest{Updates: g.piggyback(target)})
+}
+
+// Ask other alive members to ping the target, the first successful response is used
+func (g *gossip) indirectPing(ctx context.Context, target string) (*servicepb.PingResponse, error) {
+	g.mu.Lock()
+	var candidates []string
+	for id, state := range g.states {
+		if id != target && state.status == servicepb.MemberStatus_MEMBER_STATUS_ALIVE {
+			candidates = append(candidates, id)
+		}
+	}
+	g.mu.Unlock()
+
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	if len(candidates) > g.cfg.IndirectChecks {
+		candidates = candidates[:g.cfg.IndirectChecks]
+	}
+	if len(candidates) == 0 {
+		return nil, errors.New("no member available for indirect ping")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, g.cfg.PingTimeout*2)
+	defer cancel()
+
+	ch := make(chan *servicepb.PingResponse, len(candidates))
+	for _, id := range candidates {
+		go func(id string) {
+			client, err := g.cache.getClient(id)
+			if err != nil {
+				ch <- nil
+				return
+			}
+
+			res, err := client.PingReq(ctx, &servicepb.PingReqRequest{
+				Target:  target,
+				Updates: g.piggyback(target),
+			})
+			if err != nil {
+				ch <- nil
+				return
+			}
+			ch <- res
+		}(id)
+	}
+
+	for range candidates {
+		if res := <-ch; res != nil {
+			return res, nil
+		}
+	}
+	return nil, errors.New("indirect ping failed")
+}
+
+func (g *gossip) suspect(id string) *servicepb.MemberState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state, ok := g.states[id]
+	if !ok {
+		return nil
+	}
+	return &servicepb.MemberState{
+		Id:          id,
+		Status:      servicepb.MemberStatus_MEMBER_STATUS_SUSPECT,
+		Incarnation: state.incarnation,
+	}
+}
+
+func (g *gossip) expireSuspects() {
+	g.mu.Lock()
+	var deads []*servicepb.MemberState
+	for id, state := range g.states {
+		if state.status == servicepb.MemberStatus_MEMBER_STATUS_SUSPECT && time.Since(state.suspectSince) > g.cfg.SuspicionTimeout {
+			deads = append(deads, &servicepb.MemberState{
+				Id:          id,
+				Status:      servicepb.MemberStatus_MEMBER_STATUS_DEAD,
+				Incarnation: state.incarnation,
+			})
+		}
+	}
+	g.mu.Unlock()
+
+	g.apply(deads...)
+}
+
+// Merge membership updates using SWIM's precedence rules and flag the hashring for update if needed
+func (g *gossip) apply(updates ...*servicepb.MemberState) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, u := range updates {
+		if u == nil {
+			continue
+		}
+
+		// Refute suspicions about self by incrementing its incarnation number
+		if u.Id == g.cache.self.ID {
+			if u.Status != servicepb.MemberStatus_MEMBER_STATUS_ALIVE && u.Incarnation >= g.incarnation {
+				g.incarnation = u.Incarnation + 1
+				g.unsafeQueue(&servicepb.MemberState{
+					Id:          u.Id,
+					Status:      servicepb.MemberStatus_MEMBER_STATUS_ALIVE,
+					Incarnation: g.incarnation,
+				})
+			}
+			continue
+		}
+
+		state, ok := g.states[u.Id]
+		if !ok || !overrides(u, state) {
+			continue
+		}
+
+		wasDead := state.status == servicepb.MemberStatus_MEMBER_STATUS_DEAD
+		if u.Status == servicepb.MemberStatus_MEMBER_STATUS_SUSPECT && state.status != servicepb.MemberStatus_MEMBER_STATUS_SUSPECT {
+			state.suspectSince = time.Now()
+		}
+		state.status = u.Status
+		state.incarnation = u.Incarnation
+
+		if wasDead != (u.Status == servicepb.MemberStatus_MEMBER_STATUS_DEAD) {
+			g.ringDirty.Store(true)
+		}
+		g.unsafeQueue(u)
+	}
+}
+
+// Returns up to 3 pending broadcasts in addition to the current view of self and target,
+// so that a member wrongly declared dead can refute it
+func (g *gossip) piggyback(target string) []*servicepb.MemberState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	updates := []*servicepb.MemberState{{
+		Id:          g.cache.self.ID,
+		Status:      servicepb.MemberStatus_MEMBER_STATUS_ALIVE,
+		Incarnation: g.incarnation,
+	}}
+	if state, ok := g.states[target]; ok && state.status != servicepb.MemberStatus_MEMBER_STATUS_ALIVE {
+		updates = append(updates, &servicepb.MemberState{
+			Id:          target,
+			Status:      state.status,
+			Incarnation: state.incarnation,
+		})
+	}
+
+	maxTransmits := g.cfg.RetransmitMult * int(math.Ceil(math.Log2(float64(len(g.states)+2))))
+	var remaining []*broadcast
+	for i, b := range g.broadcasts {
+		if i < 3 {
+			updates = append(updates, b.state)
+			b.transmits++
+		}
+		if b.transmits < maxTransmits {
+			remaining = append(remaining, b)
+		}
+	}
+	g.broadcasts = remaining
+
+	return updates
+}
+
+// Queue an update for dissemination, replacing older updates about the same member
+func (g *gossip) unsafeQueue(state *servicepb.MemberState) {
+	for i, b := range g.broadcasts {
+		if b.state.Id == state.Id {
+			g.broadcasts = append(g.broadcasts[:i], g.broadcasts[i+1:]...)
+			break
+		}
+	}
+	g.broadcasts = append(g.broadcasts, &broadcast{state: state})
+}
+
+func (g *gossip) handlePing(updates []*servicepb.MemberState) *servicepb.PingResponse {
+	g.apply(updates...)
+	return &servicepb.PingResponse{Updates: g.piggyback("")}
+}
+
+func (g *gossip) handlePingReq(ctx context.Context, r *servicepb.PingReqRequest) (*servicepb.PingResponse, error) {
+	g.apply(r.Updates...)
+
+	res, err := g.ping(ctx, r.Target)
+	if err != nil {
+		return nil, err
+	}
+	g.apply(res.Updates...)
+
+	return res, nil
+}
+
+// Alive overrides suspect/dead only with a greater incarnation, suspect overrides alive with an equal incarnation,
+// while dead overrides everything else with an equal incarnation
+func overrides(u *servicepb.MemberState, state *memberState) bool {
+	switch u.Status {
+	case servicepb.MemberStatus_MEMBER_STATUS_ALIVE:
+		return u.Incarnation > state.incarnation
+	case servicepb.MemberStatus_MEMBER_STATUS_SUSPECT:
+		return u.Incarnation > state.incarnation ||
+			u.Incarnation == state.incarnation && state.status == servicepb.MemberStatus_MEMBER_STATUS_ALIVE
+	case servicepb.MemberStatus_MEMBER_STATUS_DEAD:
+		return u.Incarnation > state.incarnation ||
+			u.Incarnation == state.incarnation && state.status != servicepb.MemberStatus_MEMBER_STATUS_DEAD
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package nitecache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MysteriousPotato/nitecache"
+	test "github.com/MysteriousPotato/nitecache/test_utils"
+)
+
+func TestGossip(t *testing.T) {
+	ctx := context.Background()
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "3",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+
+	newNode := func(m nitecache.Member) (*nitecache.Cache, *nitecache.Table[string]) {
+		c, err := nitecache.NewCache(m, members,
+			nitecache.VirtualNodeOpt(1),
+			nitecache.HashFuncOpt(test.SimpleHashFunc),
+			nitecache.TimeoutOpt(time.Millisecond*200),
+			nitecache.GossipOpt(nitecache.GossipConfig{
+				Interval:         time.Millisecond * 20,
+				PingTimeout:      time.Millisecond * 50,
+				SuspicionTimeout: time.Millisecond * 200,
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		return c, nitecache.NewTable[string]("test").Build(c)
+	}
+
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[string], len(members))
+	for i, m := range members {
+		caches[i], tables[i] = newNode(m)
+	}
+	for _, c := range caches {
+		test.WaitForServer(t, c)
+	}
+
+	// Key "3" is owned by member "3" while it is alive
+	if err := tables[0].Put(ctx, "3", "value", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := tables[2].GetHot("3"); err != nil || v != "value" {
+		t.Fatalf("expected value %q on member 3, got: %q, err: %v", "value", v, err)
+	}
+
+	test.TearDown(t, caches[2])
+
+	waitFor(t, "member 3 to be removed from the hashring", func() bool {
+		_, err := tables[0].Get(ctx, "3")
+		return errors.Is(err, nitecache.ErrKeyNotFound)
+	})
+
+	caches[2], tables[2] = newNode(members[2])
+
+	waitFor(t, "member 3 to be added back to the hashring", func() bool {
+		if err := tables[0].Put(ctx, "3", "recovered", time.Hour); err != nil {
+			return false
+		}
+		v, err := tables[2].GetHot("3")
+		return err == nil && v == "recovered"
+	})
+
+	for _, c := range caches {
+		test.TearDown(t, c)
+	}
+}
+
+func waitFor(t *testing.T, msg string, fn func() bool) {
+	timeout := time.After(time.Second * 5)
+	for !fn() {
+		select {
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s", msg)
+		case <-time.After(time.Millisecond * 20):
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package hashring
+
+import (
+	"math"
+)
+
+// LoadFunc returns the current load of a member, i.e. its number of in-flight requests.
+type LoadFunc func(member string) int64
+
+// BoundedLoads applies consistent hashing with bounded loads on top of a [Placement].
+//
+// Every member gets a capacity of ceil((1+ε)·average load), counting the request being placed.
+// [BoundedLoads.GetOwner] returns the first member below its capacity, in the order returned by [Placement.GetOwners].
+// On a ring, this means skipping to the next point when the owner is over capacity.
+//
+// Since the total capacity always exceeds the total load, a member is always found.
+// Other methods are those of the underlying placement, so [BoundedLoads.GetOwners] ignores loads.
+//
+// The zero value is not ready for use. Refer to [NewBoundedLoads] for the factory method.
+type BoundedLoads struct {
+	Placement
+	epsilon float64
+	load    LoadFunc
+}
+
+// NewBoundedLoads wraps the placement to bound the load of every member to (1+epsilon) times the average load.
+//
+// Lower epsilons balance loads more evenly, at the cost of moving more keys away from their owner.
+func NewBoundedLoads(placement Placement, epsilon float64, load LoadFunc) *BoundedLoads {
+	return &BoundedLoads{
+		Placement: placement,
+		epsilon:   epsilon,
+		load:      load,
+	}
+}
+
+// GetOwner returns the first member responsible for the given key whose load is below its capacity.
+//
+// Unlike the underlying placement, the member returned is not necessarily the first one returned by [BoundedLoads.GetOwners].
+func (b *BoundedLoads) GetOwner(key string) (string, error) {
+	members := b.Members()
+	owners, err := b.GetOwners(key, len(members))
+	if err != nil || len(owners) == 0 {
+		return "", err
+	}
+
+	var total int64
+	loads := make(map[string]int64, len(members))
+	for _, m := range members {
+		loads[m] = b.load(m)
+		total += loads[m]
+	}
+	capacity := b.Capacity(total, len(members))
+
+	for _, owner := range owners {
+		if loads[owner] < capacity {
+			return owner, nil
+		}
+	}
+	return owners[0], nil
+}
+
+// SetWeights replaces the weights of the members, if the underlying placement is [Weighted].
+func (b *BoundedLoads) SetWeights(weights map[string]float64) error {
+	if w, ok := b.Placement.(Weighted); ok {
+		return w.SetWeights(weights)
+	}
+	return nil
+}
+
+// Capacity returns the maximum load of a member, given the total load of the given number of members.
+func (b *BoundedLoads) Capacity(total int64, members int) int64 {
+	return int64(math.Ceil((1 + b.epsilon) * float64(total+1) / float64(max(members, 1))))
+}
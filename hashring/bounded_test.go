@@ -0,0 +1,53 @@
+package hashring_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/MysteriousPotato/nitecache/hashring"
+)
+
+func TestBoundedLoads_GetOwner(t *testing.T) {
+	for _, p := range placements {
+		t.Run(p.name, func(t *testing.T) {
+			placement, err := p.new(hashring.Opt{
+				Members:      []string{"node-1", "node-2", "node-3"},
+				VirtualNodes: 10,
+				HashFunc:     hashring.DefaultHashFunc,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			loads := map[string]int64{}
+			bounded := hashring.NewBoundedLoads(placement, 0.25, func(member string) int64 {
+				return loads[member]
+			})
+
+			for i := 0; i < 100; i++ {
+				key := strconv.Itoa(i)
+				owners, err := placement.GetOwners(key, 3)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// Balanced loads, the owner is within its capacity
+				loads = map[string]int64{"node-1": 2, "node-2": 2, "node-3": 2}
+				if owner, err := bounded.GetOwner(key); err != nil || owner != owners[0] {
+					t.Fatalf("expected owner %s for key %s, got: %s, err: %v", owners[0], key, owner, err)
+				}
+
+				// Capacity is ceil(1.25 * 7 / 3) = 3
+				loads = map[string]int64{owners[0]: 3, owners[1]: 3, owners[2]: 0}
+				if owner, err := bounded.GetOwner(key); err != nil || owner != owners[2] {
+					t.Fatalf("expected owner %s for key %s, got: %s, err: %v", owners[2], key, owner, err)
+				}
+
+				loads = map[string]int64{owners[0]: 4, owners[1]: 0, owners[2]: 0}
+				if owner, err := bounded.GetOwner(key); err != nil || owner != owners[1] {
+					t.Fatalf("expected owner %s for key %s, got: %s, err: %v", owners[1], key, owner, err)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package hashring
 import (
 	"fmt"
 	"hash/fnv"
+	"maps"
+	"math"
 	"sort"
 	"strconv"
 	"sync"
@@ -14,6 +16,10 @@ type (
 		Members      []string
 		VirtualNodes int
 		HashFunc     func(key string) (int, error)
+		// Weights scale the number of virtual nodes of each member, so that members get a share of keys proportional to their weight.
+		//
+		// Only used by [Ring]. Members without a positive weight default to 1.
+		Weights map[string]float64
 	}
 	Ring struct {
 		hashFunc     func(key string) (int, error)
@@ -22,6 +28,7 @@ type (
 		mu           *sync.RWMutex
 		members      []string
 		virtualNodes int
+		weights      map[string]float64
 	}
 )
 
@@ -33,6 +40,7 @@ func New(opt Opt) (*Ring, error) {
 		mu:           &sync.RWMutex{},
 		members:      opt.Members,
 		virtualNodes: opt.VirtualNodes,
+		weights:      maps.Clone(opt.Weights),
 	}
 
 	if err := r.populate(); err != nil {
@@ -75,19 +83,76 @@ func (r *Ring) GetOwner(key string) (string, error) {
 	return r.hashMap[r.points[i]], nil
 }
 
+// GetOwners returns up to n distinct members responsible for the given key.
+//
+// The first member is always the same as the one returned by [Ring.GetOwner], the others are found by walking the ring clockwise.
+func (r *Ring) GetOwners(key string, n int) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if n > len(r.members) {
+		n = len(r.members)
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	if len(r.members) == 1 {
+		return []string{r.members[0]}, nil
+	}
+
+	sum, err := r.hashFunc(key)
+	if err != nil {
+		return nil, err
+	}
+
+	i := sort.Search(
+		len(r.points), func(i int) bool {
+			return r.points[i] >= sum
+		},
+	)
+
+	owners := make([]string, 0, n)
+	seen := make(map[string]struct{}, n)
+	for j := 0; j < len(r.points) && len(owners) < n; j++ {
+		member := r.hashMap[r.points[(i+j)%len(r.points)]]
+		if _, ok := seen[member]; ok {
+			continue
+		}
+		seen[member] = struct{}{}
+		owners = append(owners, member)
+	}
+
+	return owners, nil
+}
+
 func (r *Ring) SetMembers(newMembers []string) error {
-	//We don't need points for a single node
-	if len(newMembers) == 1 {
-		r.members = newMembers
-		r.clearPoints()
+	if SliceEquals(newMembers, r.Members()) {
 		return nil
 	}
+	return r.replace(newMembers, r.Weights())
+}
 
-	if SliceEquals(newMembers, r.members) {
+// SetWeights replaces the weights of the members. Refer to [Opt] for more details.
+//
+// Since the points of a member don't depend on other members, only the keys a member gains or loses are moved.
+func (r *Ring) SetWeights(weights map[string]float64) error {
+	if maps.Equal(weights, r.Weights()) {
 		return nil
 	}
+	return r.replace(r.Members(), weights)
+}
 
-	//Create a new Ring, in order to minimize downtime
+// Weights returns a copy of the weights of the members.
+func (r *Ring) Weights() map[string]float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return maps.Clone(r.weights)
+}
+
+func (r *Ring) replace(newMembers []string, weights map[string]float64) error {
+	//Populate a new Ring, in order to minimize downtime
 	ring := Ring{
 		mu:           &sync.RWMutex{},
 		points:       []int{},
@@ -95,15 +160,23 @@ func (r *Ring) SetMembers(newMembers []string) error {
 		members:      newMembers,
 		hashFunc:     r.hashFunc,
 		virtualNodes: r.virtualNodes,
+		weights:      maps.Clone(weights),
 	}
 
-	if err := ring.populate(); err != nil {
-		return fmt.Errorf("unable to populate hasring: %w", err)
+	//We don't need points for a single node
+	if len(newMembers) > 1 {
+		if err := ring.populate(); err != nil {
+			return fmt.Errorf("unable to populate hasring: %w", err)
+		}
 	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	*r = ring
+
+	r.members = ring.members
+	r.points = ring.points
+	r.hashMap = ring.hashMap
+	r.weights = ring.weights
 
 	return nil
 }
@@ -146,7 +219,7 @@ func (r *Ring) populate() error {
 	)
 
 	for i, m := range r.members {
-		for n := 0; n < r.virtualNodes; n++ {
+		for n := 0; n < r.memberVirtualNodes(m); n++ {
 			key := strconv.Itoa(n) + m
 
 			//Avoid collisions by prefixing the hash until a unique point is created
@@ -170,6 +243,15 @@ func (r *Ring) populate() error {
 	return nil
 }
 
+// Scale the number of virtual nodes by the weight of the member, keeping at least one
+func (r *Ring) memberVirtualNodes(member string) int {
+	weight, ok := r.weights[member]
+	if !ok || weight <= 0 {
+		return r.virtualNodes
+	}
+	return max(int(math.Round(float64(r.virtualNodes)*weight)), 1)
+}
+
 func (r *Ring) clearPoints() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -1,8 +1,13 @@
 package hashring_test
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"github.com/MysteriousPotato/nitecache/hashring"
 	"github.com/MysteriousPotato/nitecache/test_utils"
+	"math"
+	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -102,3 +107,114 @@ func TestRing_GetOwner(t *testing.T) {
 		}
 	}
 }
+
+func TestRing_GetOwners(t *testing.T) {
+	mTest := []string{"10", "20", "30"}
+	cfg := hashring.Opt{
+		Members:      mTest,
+		VirtualNodes: 10,
+		HashFunc:     test.SimpleHashFunc,
+	}
+
+	ring, err := hashring.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expected []string
+		key      string
+		n        int
+	}{{
+		expected: []string{"20"},
+		key:      "12",
+		n:        1,
+	}, {
+		expected: []string{"20", "30"},
+		key:      "12",
+		n:        2,
+	}, {
+		expected: []string{"30", "10", "20"},
+		key:      "30",
+		n:        3,
+	}, {
+		expected: []string{"10", "20", "30"},
+		key:      "100",
+		n:        5,
+	}}
+
+	for _, tt := range tests {
+		owners, err := ring.GetOwners(tt.key, tt.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(owners, tt.expected) {
+			t.Fatalf("expected owners %v for key %s, got: %v", tt.expected, tt.key, owners)
+		}
+	}
+}
+
+// Spreads points evenly, unlike FNV on similar keys, so that shares only depend on weights
+func sha256HashFunc(key string) (int, error) {
+	sum := sha256.Sum256([]byte(key))
+	return int(binary.BigEndian.Uint64(sum[:8])), nil
+}
+
+func TestRing_Weights(t *testing.T) {
+	const keys = 100_000
+
+	weights := map[string]float64{"node-1": 1, "node-2": 2, "node-3": 4}
+	ring, err := hashring.New(hashring.Opt{
+		Members:      []string{"node-1", "node-2", "node-3"},
+		VirtualNodes: 128,
+		HashFunc:     sha256HashFunc,
+		Weights:      weights,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := 128 * 7; len(ring.Points()) != expected {
+		t.Fatalf("expected %d points, got: %d", expected, len(ring.Points()))
+	}
+
+	owners := make([]string, keys)
+	counts := map[string]int{}
+	for i := range owners {
+		if owners[i], err = ring.GetOwner("key-" + strconv.Itoa(i)); err != nil {
+			t.Fatal(err)
+		}
+		counts[owners[i]]++
+	}
+
+	// Shares are proportional to weights, give or take the unevenness of the points
+	for member, weight := range weights {
+		share := float64(counts[member]) / keys
+		expected := weight / 7
+		t.Logf("%s share: %.3f (expected: %.3f)", member, share, expected)
+		if math.Abs(share-expected) > expected*0.2 {
+			t.Errorf("expected %s share to be within 20%% of %.3f, got: %.3f", member, expected, share)
+		}
+	}
+
+	if err := ring.SetWeights(map[string]float64{"node-1": 2, "node-2": 2, "node-3": 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only keys gained by node-1 are moved
+	var moved int
+	for i, prevOwner := range owners {
+		owner, err := ring.GetOwner("key-" + strconv.Itoa(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if owner == prevOwner {
+			continue
+		}
+		if owner != "node-1" {
+			t.Fatalf("expected key-%d to move to node-1, got: %s", i, owner)
+		}
+		moved++
+	}
+	t.Logf("moved keys: %.3f (optimal: %.3f)", float64(moved)/keys, 2.0/8-1.0/7)
+}
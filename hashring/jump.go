@@ -0,0 +1,92 @@
+package hashring
+
+import (
+	"sync"
+)
+
+// Jump assigns keys using jump consistent hashing.
+//
+// Members are sorted by ID and each key is mapped to one of them in O(log n), without storing any point.
+// Keys are evenly distributed without virtual nodes.
+// However, only adding or removing the member with the greatest ID moves the minimal amount of keys;
+// changing any other member moves the keys of every member sorted after it as well.
+// Replicas are the members following the owner in sorted order.
+//
+// The zero value is not ready for use. Refer to [NewJump] for the factory method.
+type Jump struct {
+	hashFunc HashFunc
+	members  []string
+	mu       *sync.RWMutex
+}
+
+// NewJump creates a jump consistent hashing placement. [Opt.VirtualNodes] is ignored.
+func NewJump(opt Opt) (*Jump, error) {
+	j := &Jump{
+		hashFunc: opt.HashFunc,
+		mu:       &sync.RWMutex{},
+	}
+	if err := j.SetMembers(opt.Members); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *Jump) GetOwner(key string) (string, error) {
+	owners, err := j.GetOwners(key, 1)
+	if err != nil {
+		return "", err
+	}
+	return owners[0], nil
+}
+
+func (j *Jump) GetOwners(key string, n int) ([]string, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	n = ownersCount(n, len(j.members))
+	if len(j.members) == 1 {
+		return []string{j.members[0]}, nil
+	}
+
+	sum, err := j.hashFunc(key)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := jumpHash(uint64(sum), len(j.members))
+	owners := make([]string, n)
+	for i := range owners {
+		owners[i] = j.members[(bucket+i)%len(j.members)]
+	}
+	return owners, nil
+}
+
+func (j *Jump) SetMembers(newMembers []string) error {
+	members := sortedMembers(newMembers)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.members = members
+	return nil
+}
+
+func (j *Jump) Members() []string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	members := make([]string, len(j.members))
+	copy(members, j.members)
+	return members
+}
+
+// jumpHash maps the key to a bucket in [0, buckets), as described in "A Fast, Minimal Memory, Consistent Hash Algorithm" (Lamping & Veach).
+func jumpHash(key uint64, buckets int) int {
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
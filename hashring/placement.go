@@ -0,0 +1,56 @@
+package hashring
+
+import (
+	"slices"
+)
+
+// Placement assigns keys to members.
+//
+// Every member must use the same placement, given the same members and options, so that they agree on the owners of a key.
+type Placement interface {
+	// GetOwner returns the member responsible for the given key.
+	GetOwner(key string) (string, error)
+	// GetOwners returns up to n distinct members responsible for the given key, starting with the one returned by GetOwner.
+	GetOwners(key string, n int) ([]string, error)
+	// SetMembers replaces the members keys are assigned to.
+	SetMembers(members []string) error
+	// Members returns a copy of the current members.
+	Members() []string
+}
+
+// Weighted is implemented by placements that assign keys to members in proportion to their weight.
+type Weighted interface {
+	// SetWeights replaces the weights of the members. Members without a positive weight default to 1.
+	SetWeights(weights map[string]float64) error
+}
+
+var (
+	_ Weighted  = (*Ring)(nil)
+	_ Weighted  = (*BoundedLoads)(nil)
+	_ Weighted  = (*ZoneAware)(nil)
+	_ Placement = (*Ring)(nil)
+	_ Placement = (*Rendezvous)(nil)
+	_ Placement = (*Jump)(nil)
+)
+
+// Sort a copy of the members, so that placements produce the same results regardless of the order members were given in
+func sortedMembers(members []string) []string {
+	sorted := slices.Clone(members)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// Clamp the number of owners between 1 and the number of members
+func ownersCount(n, members int) int {
+	return min(max(n, 1), members)
+}
+
+// mix scrambles the bits of a hash (SplitMix64 finalizer), so that combined hashes are evenly distributed
+func mix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
@@ -0,0 +1,161 @@
+package hashring_test
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/MysteriousPotato/nitecache/hashring"
+)
+
+type placementFactory struct {
+	name string
+	new  func(opt hashring.Opt) (hashring.Placement, error)
+}
+
+var placements = []placementFactory{
+	{name: "ring", new: func(opt hashring.Opt) (hashring.Placement, error) {
+		return hashring.New(opt)
+	}},
+	{name: "rendezvous", new: func(opt hashring.Opt) (hashring.Placement, error) {
+		return hashring.NewRendezvous(opt)
+	}},
+	{name: "jump", new: func(opt hashring.Opt) (hashring.Placement, error) {
+		return hashring.NewJump(opt)
+	}},
+}
+
+func TestPlacement_GetOwners(t *testing.T) {
+	for _, p := range placements {
+		t.Run(p.name, func(t *testing.T) {
+			placement, err := p.new(hashring.Opt{
+				Members:      []string{"node-3", "node-1", "node-2"},
+				VirtualNodes: 10,
+				HashFunc:     hashring.DefaultHashFunc,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The order of members must not matter
+			shuffled, err := p.new(hashring.Opt{
+				Members:      []string{"node-2", "node-3", "node-1"},
+				VirtualNodes: 10,
+				HashFunc:     hashring.DefaultHashFunc,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 100; i++ {
+				key := strconv.Itoa(i)
+				owner, err := placement.GetOwner(key)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				owners, err := placement.GetOwners(key, 5)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(owners) != 3 || owners[0] != owner {
+					t.Fatalf("expected 3 owners starting with %s for key %s, got: %v", owner, key, owners)
+				}
+				if !hashring.SliceEquals(owners, placement.Members()) {
+					t.Fatalf("expected distinct owners for key %s, got: %v", key, owners)
+				}
+
+				shuffledOwners, err := shuffled.GetOwners(key, 5)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(owners, shuffledOwners) {
+					t.Fatalf("expected owners %v for key %s regardless of members order, got: %v", owners, key, shuffledOwners)
+				}
+			}
+		})
+	}
+}
+
+// Report how evenly keys are distributed, and how many keys move when a member is added
+func TestPlacement_Distribution(t *testing.T) {
+	const keys = 100_000
+
+	tests := []struct {
+		placement placementFactory
+		// Whether the distribution is expected to be close to optimal, rather than only reported
+		balanced bool
+	}{
+		// Points of the ring are not evenly spread with so few virtual nodes
+		{placement: placements[0], balanced: false},
+		{placement: placements[1], balanced: true},
+		{placement: placements[2], balanced: true},
+	}
+
+	for _, tt := range tests {
+		for _, size := range []int{3, 5, 10} {
+			t.Run(fmt.Sprintf("%s/%d members", tt.placement.name, size), func(t *testing.T) {
+				members := make([]string, size)
+				for i := range members {
+					members[i] = fmt.Sprintf("node-%02d", i)
+				}
+
+				placement, err := tt.placement.new(hashring.Opt{
+					Members:      members,
+					VirtualNodes: 32,
+					HashFunc:     hashring.DefaultHashFunc,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				owners := make([]string, keys)
+				counts := map[string]int{}
+				for i := range owners {
+					if owners[i], err = placement.GetOwner("key-" + strconv.Itoa(i)); err != nil {
+						t.Fatal(err)
+					}
+					counts[owners[i]]++
+				}
+
+				mean := float64(keys) / float64(size)
+				var variance float64
+				for _, m := range members {
+					variance += math.Pow(float64(counts[m])-mean, 2) / float64(size)
+				}
+				cv := math.Sqrt(variance) / mean
+
+				// Adding the member with the greatest ID is the best case for jump hashing
+				if err := placement.SetMembers(append(members, fmt.Sprintf("node-%02d", size))); err != nil {
+					t.Fatal(err)
+				}
+
+				var moved int
+				for i, prevOwner := range owners {
+					owner, err := placement.GetOwner("key-" + strconv.Itoa(i))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if owner != prevOwner {
+						moved++
+					}
+				}
+				movedRatio := float64(moved) / keys
+				optimalRatio := 1 / float64(size+1)
+
+				t.Logf("coefficient of variation: %.3f, moved keys: %.3f (optimal: %.3f)", cv, movedRatio, optimalRatio)
+				if !tt.balanced {
+					return
+				}
+				if cv > 0.05 {
+					t.Errorf("expected coefficient of variation below %.3f, got: %.3f", 0.05, cv)
+				}
+				if movedRatio > optimalRatio*1.5 {
+					t.Errorf("expected at most %.3f of keys to move, got: %.3f", optimalRatio*1.5, movedRatio)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package hashring
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+type (
+	// Rendezvous assigns keys using rendezvous hashing (highest random weight).
+	//
+	// Every member is scored for a given key, and the member with the highest score owns it.
+	// Keys are evenly distributed without virtual nodes, and only the keys of a removed member (or the keys taken by an added member) move.
+	// Finding the owner of a key is O(n) with the number of members.
+	//
+	// The zero value is not ready for use. Refer to [NewRendezvous] for the factory method.
+	Rendezvous struct {
+		hashFunc HashFunc
+		members  []rendezvousMember
+		mu       *sync.RWMutex
+	}
+	rendezvousMember struct {
+		id   string
+		hash uint64
+	}
+)
+
+// NewRendezvous creates a rendezvous placement. [Opt.VirtualNodes] is ignored.
+func NewRendezvous(opt Opt) (*Rendezvous, error) {
+	r := &Rendezvous{
+		hashFunc: opt.HashFunc,
+		mu:       &sync.RWMutex{},
+	}
+	if err := r.SetMembers(opt.Members); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Rendezvous) GetOwner(key string) (string, error) {
+	owners, err := r.GetOwners(key, 1)
+	if err != nil {
+		return "", err
+	}
+	return owners[0], nil
+}
+
+// GetOwners returns up to n distinct members responsible for the given key, by decreasing score.
+func (r *Rendezvous) GetOwners(key string, n int) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	n = ownersCount(n, len(r.members))
+	if len(r.members) == 1 {
+		return []string{r.members[0].id}, nil
+	}
+
+	sum, err := r.hashFunc(key)
+	if err != nil {
+		return nil, err
+	}
+
+	type score struct {
+		id    string
+		value uint64
+	}
+	scores := make([]score, len(r.members))
+	for i, m := range r.members {
+		scores[i] = score{id: m.id, value: mix(uint64(sum) ^ m.hash)}
+	}
+	slices.SortFunc(scores, func(a, b score) int {
+		if c := cmp.Compare(b.value, a.value); c != 0 {
+			return c
+		}
+		// Break ties consistently
+		return strings.Compare(a.id, b.id)
+	})
+
+	owners := make([]string, n)
+	for i := range owners {
+		owners[i] = scores[i].id
+	}
+	return owners, nil
+}
+
+func (r *Rendezvous) SetMembers(newMembers []string) error {
+	members := make([]rendezvousMember, 0, len(newMembers))
+	for _, id := range sortedMembers(newMembers) {
+		hash, err := r.hashFunc(id)
+		if err != nil {
+			return fmt.Errorf("unable to hash member %s: %w", id, err)
+		}
+		members = append(members, rendezvousMember{id: id, hash: mix(uint64(hash))})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.members = members
+	return nil
+}
+
+func (r *Rendezvous) Members() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := make([]string, len(r.members))
+	for i, m := range r.members {
+		members[i] = m.id
+	}
+	return members
+}
@@ -0,0 +1,93 @@
+package hashring
+
+import (
+	"maps"
+	"sync"
+)
+
+// ZoneAware spreads the owners of a key across zones on top of a [Placement].
+//
+// [ZoneAware.GetOwners] picks owners in distinct zones first, in the order returned by the underlying placement,
+// then fills the remaining owners regardless of zones. The first owner is left unchanged.
+// Members without a zone are considered to share the same zone.
+//
+// The zero value is not ready for use. Refer to [NewZoneAware] for the factory method.
+type ZoneAware struct {
+	Placement
+	zones map[string]string
+	mu    *sync.RWMutex
+}
+
+// NewZoneAware wraps the placement to spread owners across the zones of the members, indexed by member.
+func NewZoneAware(placement Placement, zones map[string]string) *ZoneAware {
+	return &ZoneAware{
+		Placement: placement,
+		zones:     maps.Clone(zones),
+		mu:        &sync.RWMutex{},
+	}
+}
+
+// GetOwners returns up to n distinct members responsible for the given key, spread across as many zones as possible.
+func (z *ZoneAware) GetOwners(key string, n int) ([]string, error) {
+	z.mu.RLock()
+	defer z.mu.RUnlock()
+
+	if n <= 1 || len(z.zones) == 0 {
+		return z.Placement.GetOwners(key, n)
+	}
+
+	members := z.Members()
+	candidates, err := z.Placement.GetOwners(key, len(members))
+	if err != nil {
+		return nil, err
+	}
+	n = ownersCount(n, len(candidates))
+
+	owners := make([]string, 0, n)
+	picked := make(map[string]bool, n)
+	seenZones := map[string]bool{}
+	for _, m := range candidates {
+		if len(owners) == n {
+			break
+		}
+		if zone := z.zones[m]; !seenZones[zone] {
+			seenZones[zone] = true
+			picked[m] = true
+			owners = append(owners, m)
+		}
+	}
+	for _, m := range candidates {
+		if len(owners) == n {
+			break
+		}
+		if !picked[m] {
+			owners = append(owners, m)
+		}
+	}
+
+	return owners, nil
+}
+
+// SetZones replaces the zones of the members, indexed by member.
+func (z *ZoneAware) SetZones(zones map[string]string) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	z.zones = maps.Clone(zones)
+}
+
+// Zone returns the zone of the given member, or an empty string if it has none.
+func (z *ZoneAware) Zone(member string) string {
+	z.mu.RLock()
+	defer z.mu.RUnlock()
+
+	return z.zones[member]
+}
+
+// SetWeights replaces the weights of the members, if the underlying placement is [Weighted].
+func (z *ZoneAware) SetWeights(weights map[string]float64) error {
+	if w, ok := z.Placement.(Weighted); ok {
+		return w.SetWeights(weights)
+	}
+	return nil
+}
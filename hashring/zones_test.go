@@ -0,0 +1,72 @@
+package hashring_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/MysteriousPotato/nitecache/hashring"
+)
+
+func TestZoneAware_GetOwners(t *testing.T) {
+	zones := map[string]string{
+		"node-1": "a", "node-2": "a",
+		"node-3": "b", "node-4": "b",
+		"node-5": "c", "node-6": "c",
+	}
+
+	for _, p := range placements {
+		t.Run(p.name, func(t *testing.T) {
+			placement, err := p.new(hashring.Opt{
+				Members:      []string{"node-1", "node-2", "node-3", "node-4", "node-5", "node-6"},
+				VirtualNodes: 10,
+				HashFunc:     hashring.DefaultHashFunc,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			zoneAware := hashring.NewZoneAware(placement, zones)
+
+			for i := 0; i < 100; i++ {
+				key := strconv.Itoa(i)
+				owner, err := placement.GetOwner(key)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				owners, err := zoneAware.GetOwners(key, 3)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(owners) != 3 || owners[0] != owner {
+					t.Fatalf("expected 3 owners starting with %s for key %s, got: %v", owner, key, owners)
+				}
+				if zones[owners[0]] == zones[owners[1]] || zones[owners[0]] == zones[owners[2]] || zones[owners[1]] == zones[owners[2]] {
+					t.Fatalf("expected owners in distinct zones for key %s, got: %v", key, owners)
+				}
+
+				// Once every zone has an owner, the remaining owners are picked regardless of zones
+				allOwners, err := zoneAware.GetOwners(key, 10)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(allOwners[:3], owners) || !hashring.SliceEquals(allOwners, placement.Members()) {
+					t.Fatalf("expected every member starting with %v for key %s, got: %v", owners, key, allOwners)
+				}
+			}
+
+			// Without zones, owners are those of the underlying placement
+			zoneAware.SetZones(nil)
+			for i := 0; i < 100; i++ {
+				key := strconv.Itoa(i)
+				expected, err := placement.GetOwners(key, 3)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if owners, err := zoneAware.GetOwners(key, 3); err != nil || !reflect.DeepEqual(owners, expected) {
+					t.Fatalf("expected owners %v for key %s, got: %v, err: %v", expected, key, owners, err)
+				}
+			}
+		})
+	}
+}
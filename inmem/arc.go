@@ -0,0 +1,225 @@
+package inmem
+
+import (
+	"container/list"
+	"sync"
+)
+
+const (
+	arcT1 = iota
+	arcT2
+	arcB1
+	arcB2
+)
+
+type (
+	// ARC cache (adaptive replacement cache)
+	//
+	// Entries accessed once are kept in a recency list (T1), entries accessed more than once in a frequency list (T2).
+	// The keys of entries evicted from each list are remembered in ghost lists (B1 and B2).
+	// A hit in a ghost list grows the target size of the corresponding list, so that the balance between recency and frequency adapts to the access pattern.
+	//
+	// The zero value is not ready for use. Refer to [NewARC] for the factory method.
+	ARC[T comparable, K any] struct {
+		threshold int
+		// Target size of T1
+		p       int
+		lists   [4]*list.List
+		hashMap map[T]*list.Element
+		mu      *sync.Mutex
+	}
+	arcEntry[T comparable, K any] struct {
+		key   T
+		value K
+		list  int
+	}
+)
+
+// NewARC creates an in memory cache that applies an ARC policy.
+//
+// The ghost lists remember up to threshold keys in addition to the stored entries.
+func NewARC[T comparable, K any](threshold int) *ARC[T, K] {
+	return &ARC[T, K]{
+		threshold: threshold,
+		lists:     [4]*list.List{list.New(), list.New(), list.New(), list.New()},
+		hashMap:   make(map[T]*list.Element),
+		mu:        &sync.Mutex{},
+	}
+}
+
+func (a *ARC[T, K]) Get(key T, opts ...Opt) (K, bool) {
+	o := getOpts(opts...)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ele, ok := a.hashMap[key]
+	if !ok || !a.isResident(ele) {
+		var empty K
+		return empty, false
+	}
+
+	if !o.skipInc {
+		ele = a.unsafeMove(ele, arcT2)
+	}
+	return ele.Value.(*arcEntry[T, K]).value, true
+}
+
+func (a *ARC[T, K]) Put(key T, value K, opts ...Opt) bool {
+	o := getOpts(opts...)
+	defer o.notifyEvicted()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ele, ok := a.hashMap[key]
+	if ok && a.isResident(ele) {
+		ele.Value.(*arcEntry[T, K]).value = value
+		if !o.skipInc {
+			a.unsafeMove(ele, arcT2)
+		}
+		return true
+	}
+
+	if a.threshold <= 0 {
+		queueEvicted(o, key, value)
+		return false
+	}
+
+	t1, t2, b1, b2 := a.lists[arcT1].Len(), a.lists[arcT2].Len(), a.lists[arcB1].Len(), a.lists[arcB2].Len()
+	switch {
+	case ok && ele.Value.(*arcEntry[T, K]).list == arcB1:
+		// Recently evicted from T1, favor recency
+		a.p = min(a.threshold, a.p+max(b2/b1, 1))
+		a.unsafeReplace(o, false)
+	case ok:
+		// Recently evicted from T2, favor frequency
+		a.p = max(0, a.p-max(b1/b2, 1))
+		a.unsafeReplace(o, true)
+	default:
+		if t1+b1 >= a.threshold {
+			if t1 < a.threshold {
+				a.unsafeRemove(a.lists[arcB1].Front())
+				a.unsafeReplace(o, false)
+			} else {
+				evicted := a.lists[arcT1].Front().Value.(*arcEntry[T, K])
+				a.unsafeRemove(a.lists[arcT1].Front())
+				queueEvicted(o, evicted.key, evicted.value)
+			}
+		} else if total := t1 + t2 + b1 + b2; total >= a.threshold {
+			if total >= 2*a.threshold {
+				a.unsafeRemove(a.lists[arcB2].Front())
+			}
+			a.unsafeReplace(o, false)
+		}
+
+		a.hashMap[key] = a.lists[arcT1].PushBack(&arcEntry[T, K]{key: key, value: value, list: arcT1})
+		return false
+	}
+
+	// Ghost hits are stored in T2, since the key was accessed before
+	ele = a.unsafeMove(ele, arcT2)
+	ele.Value.(*arcEntry[T, K]).value = value
+	return false
+}
+
+func (a *ARC[T, K]) Evict(key T) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ele, ok := a.hashMap[key]
+	if !ok || !a.isResident(ele) {
+		return false
+	}
+
+	a.unsafeRemove(ele)
+	return true
+}
+
+func (a *ARC[T, K]) Inc(key T) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if ele, ok := a.hashMap[key]; ok && a.isResident(ele) {
+		a.unsafeMove(ele, arcT2)
+		return true
+	}
+	return false
+}
+
+func (a *ARC[T, K]) Values() map[T]K {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	values := make(map[T]K, a.unsafeLen())
+	for _, l := range a.lists[:arcB1] {
+		for ele := l.Front(); ele != nil; ele = ele.Next() {
+			entry := ele.Value.(*arcEntry[T, K])
+			values[entry.key] = entry.value
+		}
+	}
+	return values
+}
+
+func (a *ARC[T, K]) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.unsafeLen()
+}
+
+// Not concurrently safe!
+func (a *ARC[T, K]) unsafeLen() int {
+	return a.lists[arcT1].Len() + a.lists[arcT2].Len()
+}
+
+// Whether the entry is stored, as opposed to only being remembered in a ghost list
+func (a *ARC[T, K]) isResident(ele *list.Element) bool {
+	return ele.Value.(*arcEntry[T, K]).list <= arcT2
+}
+
+// Not concurrently safe!
+// Evicts the LRU entry of T1 or T2 to the corresponding ghost list, depending on the target size of T1
+func (a *ARC[T, K]) unsafeReplace(o *opts, ghostHitInB2 bool) {
+	// There is still room, i.e. following explicit evictions
+	if a.unsafeLen() < a.threshold {
+		return
+	}
+
+	from, to := arcT2, arcB2
+	if t1 := a.lists[arcT1].Len(); t1 > 0 && (t1 > a.p || (ghostHitInB2 && t1 == a.p)) || a.lists[arcT2].Len() == 0 {
+		from, to = arcT1, arcB1
+	}
+
+	evicted := *a.lists[from].Front().Value.(*arcEntry[T, K])
+	a.unsafeMove(a.lists[from].Front(), to)
+	queueEvicted(o, evicted.key, evicted.value)
+}
+
+// Not concurrently safe!
+// Moves the entry to the MRU position of the given list. Values are dropped when moving to a ghost list.
+func (a *ARC[T, K]) unsafeMove(ele *list.Element, to int) *list.Element {
+	entry := ele.Value.(*arcEntry[T, K])
+	if entry.list == to {
+		a.lists[to].MoveToBack(ele)
+		return ele
+	}
+
+	a.lists[entry.list].Remove(ele)
+	entry.list = to
+	if to >= arcB1 {
+		var empty K
+		entry.value = empty
+	}
+
+	ele = a.lists[to].PushBack(entry)
+	a.hashMap[entry.key] = ele
+	return ele
+}
+
+// Not concurrently safe!
+func (a *ARC[T, K]) unsafeRemove(ele *list.Element) {
+	entry := ele.Value.(*arcEntry[T, K])
+	a.lists[entry.list].Remove(ele)
+	delete(a.hashMap, entry.key)
+}
@@ -0,0 +1,99 @@
+package inmem_test
+
+import (
+	"github.com/MysteriousPotato/nitecache/inmem"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestARC(t *testing.T) {
+	arc := inmem.NewARC[int, int](4)
+	for i := 0; i < 4; i++ {
+		if exists := arc.Put(i, i); exists {
+			t.Fatalf("Expected exists %t, got %t for put operation", false, exists)
+		}
+	}
+
+	// Keys accessed more than once are kept over keys accessed once
+	arc.Get(0)
+	arc.Get(1)
+	arc.Put(4, 4)
+	arc.Put(5, 5)
+
+	expected := map[int]int{0: 0, 1: 1, 4: 4, 5: 5}
+	if got := arc.Values(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected %v\ngot %v", expected, got)
+	}
+
+	// Keys evicted recently are remembered and stored as frequently used
+	arc.Put(2, 20)
+	if v, ok := arc.Get(2); !ok || v != 20 {
+		t.Fatalf("Expected %d for key %d, got %d", 20, 2, v)
+	}
+
+	if exists := arc.Evict(2); !exists {
+		t.Fatalf("Expected exists %t, got %t for evict operation", true, exists)
+	}
+	if exists := arc.Evict(2); exists {
+		t.Fatalf("Expected exists %t, got %t for evict operation", false, exists)
+	}
+	if l := arc.Len(); l != 3 {
+		t.Fatalf("Expected %d entries, got %d", 3, l)
+	}
+}
+
+func TestARCScanResistance(t *testing.T) {
+	arc := inmem.NewARC[int, int](100)
+	for i := 0; i < 2; i++ {
+		for key := 0; key < 50; key++ {
+			if _, ok := arc.Get(key); !ok {
+				arc.Put(key, key, inmem.SkipInc(true))
+			}
+		}
+	}
+
+	for key := 1000; key < 2000; key++ {
+		if _, ok := arc.Get(key); !ok {
+			arc.Put(key, key, inmem.SkipInc(true))
+		}
+	}
+
+	values := arc.Values()
+	if len(values) != 100 {
+		t.Fatalf("Expected %d entries, got %d", 100, len(values))
+	}
+	for key := 0; key < 50; key++ {
+		if _, ok := values[key]; !ok {
+			t.Fatalf("Expected frequently used key %d to be retained, got %v", key, values)
+		}
+	}
+}
+
+func TestARCConcurrentAccess(t *testing.T) {
+	goroutinesCount := 100
+	iterations := 1000
+
+	arc := inmem.NewARC[int, int](128)
+	wg := sync.WaitGroup{}
+
+	wg.Add(goroutinesCount)
+	for i := 0; i < goroutinesCount; i++ {
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < iterations; j++ {
+				arc.Put(j, j)
+				arc.Get(j)
+				arc.Inc(j)
+				arc.Evict(j)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if values := arc.Values(); !reflect.DeepEqual(values, map[int]int{}) {
+		t.Fatalf("Expected no values, got %v", values)
+	}
+}
@@ -1,6 +1,9 @@
 package inmem
 
-import "sync"
+import (
+	"maps"
+	"sync"
+)
 
 // Cache is essentially a wrapper around map[T]K that support concurrent safety
 type Cache[T comparable, K any] struct {
@@ -44,4 +47,18 @@ func (c *Cache[T, K]) Evict(key T) bool {
 	return false
 }
 
+func (c *Cache[T, K]) Values() map[T]K {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return maps.Clone(c.internal)
+}
+
+func (c *Cache[T, K]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.internal)
+}
+
 func (c *Cache[T, K]) Inc(_ string) bool { return false }
@@ -0,0 +1,170 @@
+package inmem
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+type (
+	// expirationQueue orders keys by expiration time, holding at most one entry per key.
+	expirationQueue[K comparable] struct {
+		mu      sync.Mutex
+		entries expirationHeap[K]
+		index   map[K]*expiration[K]
+	}
+	expiration[K comparable] struct {
+		key K
+		at  time.Time
+		i   int
+	}
+	expirationHeap[K comparable] []*expiration[K]
+)
+
+// WithExpirationSweep removes expired items in the background, checking for them at the given interval.
+//
+// Otherwise, expired items are only detected when accessed, and remain stored until they are replaced or evicted.
+// Items are tracked by expiration time, so that each sweep only visits the items that expired since the last one.
+//
+// The sweep must be stopped using [Store.Close].
+func WithExpirationSweep[K comparable, V any](interval time.Duration) StoreOpt[K, V] {
+	return func(s *Store[K, V]) {
+		s.sweepInterval = interval
+	}
+}
+
+// Close stops the expiration sweep, if enabled.
+func (s Store[K, V]) Close() {
+	if s.stopSweep != nil {
+		s.stopSweep()
+	}
+}
+
+// Expired returns the number of expired items removed by the expiration sweep.
+func (s Store[K, V]) Expired() int64 {
+	return s.expired.Load()
+}
+
+func (s *Store[K, V]) startSweep() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	s.stopSweep = sync.OnceFunc(func() {
+		cancel()
+		<-done
+	})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(s.sweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.sweep()
+			}
+		}
+	}()
+}
+
+func (s Store[K, V]) sweep() {
+	var evicted []eviction[K, V]
+	for _, key := range s.expirations.popDue(time.Now()) {
+		s.lock.LockKey(key)
+		// The item may have been replaced since it was scheduled
+		if item, ok := s.internal.Get(key, SkipInc(true)); ok && s.isExpiredForGood(item) {
+			s.internal.Evict(key)
+			s.expired.Add(1)
+			evicted = append(evicted, eviction[K, V]{key: key, item: item, reason: EvictExpired})
+		}
+		s.lock.UnlockKey(key)
+	}
+	s.notifyEvicted(&evicted)
+}
+
+// Expired items can still be served during the stale-while-revalidate window
+func (s Store[K, V]) isExpiredForGood(item Item[V]) bool {
+	return item.IsExpired() && time.Since(item.Expire) >= s.staleWhileRevalidate
+}
+
+// Make sure to lock the key before using this
+func (s Store[K, V]) unsafeSchedule(key K, item Item[V]) {
+	if s.expirations == nil {
+		return
+	}
+
+	if item.Expire.IsZero() {
+		s.expirations.remove(key)
+		return
+	}
+	s.expirations.set(key, item.Expire.Add(s.staleWhileRevalidate))
+}
+
+func newExpirationQueue[K comparable]() *expirationQueue[K] {
+	return &expirationQueue[K]{index: map[K]*expiration[K]{}}
+}
+
+func (q *expirationQueue[K]) set(key K, at time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if e, ok := q.index[key]; ok {
+		e.at = at
+		heap.Fix(&q.entries, e.i)
+		return
+	}
+
+	e := &expiration[K]{key: key, at: at}
+	q.index[key] = e
+	heap.Push(&q.entries, e)
+}
+
+func (q *expirationQueue[K]) remove(key K) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if e, ok := q.index[key]; ok {
+		heap.Remove(&q.entries, e.i)
+		delete(q.index, key)
+	}
+}
+
+// Remove and return the keys expiring before now
+func (q *expirationQueue[K]) popDue(now time.Time) []K {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var keys []K
+	for len(q.entries) > 0 && q.entries[0].at.Before(now) {
+		e := heap.Pop(&q.entries).(*expiration[K])
+		delete(q.index, e.key)
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+func (h expirationHeap[K]) Len() int           { return len(h) }
+func (h expirationHeap[K]) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h expirationHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].i = i
+	h[j].i = j
+}
+
+func (h *expirationHeap[K]) Push(x any) {
+	e := x.(*expiration[K])
+	e.i = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expirationHeap[K]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
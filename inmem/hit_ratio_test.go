@@ -0,0 +1,60 @@
+package inmem_test
+
+import (
+	"github.com/MysteriousPotato/nitecache/inmem"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+type benchStorage interface {
+	Get(key uint64, opts ...inmem.Opt) (uint64, bool)
+	Put(key uint64, value uint64, opts ...inmem.Opt) bool
+}
+
+// Compare the hit ratio of the storages on zipfian traces, reported as "hit%"
+func BenchmarkHitRatio(b *testing.B) {
+	storages := []struct {
+		name       string
+		newStorage func(threshold int) benchStorage
+	}{
+		{name: "LRU", newStorage: func(threshold int) benchStorage {
+			return inmem.NewLRU[uint64, uint64](threshold)
+		}},
+		{name: "LFU", newStorage: func(threshold int) benchStorage {
+			return inmem.NewLFU[uint64, uint64](threshold)
+		}},
+		{name: "TinyLFU", newStorage: func(threshold int) benchStorage {
+			return inmem.NewTinyLFU[uint64, uint64](threshold)
+		}},
+		{name: "S3FIFO", newStorage: func(threshold int) benchStorage {
+			return inmem.NewS3FIFO[uint64, uint64](threshold)
+		}},
+		{name: "ARC", newStorage: func(threshold int) benchStorage {
+			return inmem.NewARC[uint64, uint64](threshold)
+		}},
+	}
+
+	for _, s := range []float64{1.01, 1.1, 1.5} {
+		for _, threshold := range []int{100, 1000, 10000} {
+			for _, storage := range storages {
+				b.Run(storage.name+"/s="+strconv.FormatFloat(s, 'f', -1, 64)+"/threshold="+strconv.Itoa(threshold), func(b *testing.B) {
+					zipf := rand.NewZipf(rand.New(rand.NewSource(1)), s, 1, uint64(threshold*100))
+					cache := storage.newStorage(threshold)
+
+					var hits int
+					for i := 0; i < b.N; i++ {
+						key := zipf.Uint64()
+						if _, ok := cache.Get(key); ok {
+							hits++
+							continue
+						}
+						cache.Put(key, key, inmem.SkipInc(true))
+					}
+
+					b.ReportMetric(float64(hits)/float64(b.N)*100, "hit%")
+				})
+			}
+		}
+	}
+}
@@ -11,6 +11,7 @@ type (
 	// The zero value is not ready for use. Refer to [NewLFU] for the factory method.
 	LFU[T comparable, K any] struct {
 		threshold int
+		sizer     Sizer[T, K]
 		size      int
 		freqList  *list.List
 		hashMap   map[T]*lfuEntry[T, K]
@@ -19,6 +20,7 @@ type (
 	lfuEntry[T comparable, K any] struct {
 		key     T
 		value   K
+		cost    int
 		nodeKey *list.Element
 		parent  *list.Element
 	}
@@ -40,6 +42,15 @@ func NewLFU[T comparable, K any](threshold int) *LFU[T, K] {
 	}
 }
 
+// NewLFUBytes creates an in memory cache that applies an LFU policy, constrained by the total cost of its entries rather than their number.
+//
+// The cost of each entry is computed by sizer. Entries costing more than maxBytes are not stored.
+func NewLFUBytes[T comparable, K any](maxBytes int, sizer Sizer[T, K]) *LFU[T, K] {
+	l := NewLFU[T, K](maxBytes)
+	l.sizer = sizer
+	return l
+}
+
 func (l *LFU[T, K]) Get(key T, opts ...Opt) (K, bool) {
 	o := getOpts(opts...)
 
@@ -76,23 +87,37 @@ func (l *LFU[T, K]) Get(key T, opts ...Opt) (K, bool) {
 
 func (l *LFU[T, K]) Put(key T, value K, opts ...Opt) bool {
 	o := getOpts(opts...)
+	defer o.notifyEvicted()
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	// Upsert the entry and update cache size
+	cost := l.cost(key, value)
 	entry, ok := l.hashMap[key]
+	if cost > l.threshold {
+		if ok {
+			l.unsafeRemove(entry)
+		}
+		queueEvicted(o, key, value)
+		return ok
+	}
+
+	// Upsert the entry and update cache size
 	if ok {
+		l.size += cost - entry.cost
 		entry.value = value
+		entry.cost = cost
+		// The entry is already part of the frequency list, so it must be counted before possibly being evicted
+		if !o.skipInc {
+			l.unsafeUpdateCount(entry, false)
+		}
+		l.unsafeApplyPolicy(o)
 	} else {
-		entry = &lfuEntry[T, K]{key: key, value: value}
+		entry = &lfuEntry[T, K]{key: key, value: value, cost: cost}
 		l.hashMap[key] = entry
-		l.size += 1
-	}
-	l.unsafeApplyPolicy()
-
-	if !ok || !o.skipInc {
-		l.unsafeUpdateCount(entry, !ok)
+		l.size += cost
+		l.unsafeApplyPolicy(o)
+		l.unsafeUpdateCount(entry, true)
 	}
 	return ok
 }
@@ -101,9 +126,8 @@ func (l *LFU[T, K]) Evict(key T) bool {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	if value, ok := l.hashMap[key]; ok {
-		delete(l.hashMap, value.key)
-		l.unsafeRemoveFreqEntry(value.parent, value.nodeKey)
+	if entry, ok := l.hashMap[key]; ok {
+		l.unsafeRemove(entry)
 		return true
 	}
 	return false
@@ -124,13 +148,42 @@ func (l *LFU[T, K]) Values() map[T]K {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
-	values := make(map[T]K, l.size)
+	values := make(map[T]K, len(l.hashMap))
 	for k, v := range l.hashMap {
 		values[k] = v.value
 	}
 	return values
 }
 
+func (l *LFU[T, K]) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.hashMap)
+}
+
+// Bytes returns the total cost of the entries, as computed by the [Sizer].
+//
+// Returns 0 if the LFU was not created using [NewLFUBytes].
+func (l *LFU[T, K]) Bytes() int {
+	if l.sizer == nil {
+		return 0
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.size
+}
+
+// Entries cost 1 unless a sizer is set
+func (l *LFU[T, K]) cost(key T, value K) int {
+	if l.sizer == nil {
+		return 1
+	}
+	return l.sizer(key, value)
+}
+
 func (l *LFU[T, K]) unsafeUpdateCount(entry *lfuEntry[T, K], isNewEntry bool) {
 	var currentNode, prevNode *list.Element
 	var nextCount int
@@ -160,19 +213,28 @@ func (l *LFU[T, K]) unsafeUpdateCount(entry *lfuEntry[T, K], isNewEntry bool) {
 }
 
 // Not concurrently safe!
-func (l *LFU[T, K]) unsafeApplyPolicy() {
+func (l *LFU[T, K]) unsafeApplyPolicy(o *opts) {
 	for l.size > l.threshold {
 		node := l.freqList.Front()
 		nodeValue := node.Value.(*lfuNode[T])
-		entry := nodeValue.keys.Back()
-
-		l.size -= 1
-		delete(l.hashMap, entry.Value.(T))
+		key := nodeValue.keys.Back()
 
-		l.unsafeRemoveFreqEntry(node, entry)
+		if entry, ok := l.hashMap[key.Value.(T)]; ok {
+			l.size -= entry.cost
+			delete(l.hashMap, entry.key)
+			queueEvicted(o, entry.key, entry.value)
+		}
+		l.unsafeRemoveFreqEntry(node, key)
 	}
 }
 
+// Not concurrently safe!
+func (l *LFU[T, K]) unsafeRemove(entry *lfuEntry[T, K]) {
+	l.size -= entry.cost
+	delete(l.hashMap, entry.key)
+	l.unsafeRemoveFreqEntry(entry.parent, entry.nodeKey)
+}
+
 // Not concurrently safe!
 // Removes a specific entry from a given freqList node
 func (l *LFU[T, K]) unsafeRemoveFreqEntry(node *list.Element, entry *list.Element) {
@@ -4,6 +4,7 @@ import (
 	"github.com/MysteriousPotato/nitecache/inmem"
 	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -155,3 +156,46 @@ func BenchmarkLFUInc(b *testing.B) {
 		})
 	}
 }
+
+func TestLFUBytes(t *testing.T) {
+	lfu := inmem.NewLFUBytes[string, string](10, func(key string, value string) int {
+		return len(key) + len(value)
+	})
+
+	lfu.Put("a", "1234") // 5 bytes
+	lfu.Put("b", "1234") // 10 bytes
+	lfu.Get("a")
+	lfu.Get("a")
+	lfu.Get("b")
+	lfu.Put("c", "12")              // 13 bytes, evicts "b" which is less frequently used than "a"
+	lfu.Put("d", "123456789012")    // Larger than the budget, never stored
+	lfu.Put("e", "1")               // 10 bytes
+	lfu.Put("f", "1")               // 12 bytes, evicts "c" which is the oldest of the least frequently used
+	lfu.Put("a", "123456789012345") // Larger than the budget, evicts the previous value
+
+	expected := map[string]string{"e": "1", "f": "1"}
+	if got := lfu.Values(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v\ngot %v", expected, got)
+	}
+	if got := lfu.Bytes(); got != 4 {
+		t.Fatalf("expected %d bytes, got %d", 4, got)
+	}
+	if got := lfu.Len(); got != 2 {
+		t.Fatalf("expected %d entries, got %d", 2, got)
+	}
+}
+
+func TestLFUBytesPromotions(t *testing.T) {
+	lfu := inmem.NewLFUBytes[int, string](20, func(_ int, value string) int {
+		return len(value)
+	})
+
+	// Entries are promoted repeatedly, so evictions go through every frequency node
+	for i := 0; i < 1000; i++ {
+		lfu.Put(i%7, strings.Repeat("x", i%5+1))
+		lfu.Get(i % 3)
+		if got := lfu.Bytes(); got > 20 {
+			t.Fatalf("expected at most %d bytes, got %d", 20, got)
+		}
+	}
+}
@@ -11,6 +11,7 @@ type (
 	// The zero value is not ready for use. Refer to [NewLRU] for the factory method.
 	LRU[T comparable, K any] struct {
 		threshold     int
+		sizer         Sizer[T, K]
 		evictionQueue *list.List
 		hashMap       map[T]*list.Element
 		size          int
@@ -19,6 +20,7 @@ type (
 	node[T comparable, K any] struct {
 		key   T
 		value K
+		cost  int
 	}
 )
 
@@ -32,6 +34,15 @@ func NewLRU[T comparable, K any](threshold int) *LRU[T, K] {
 	}
 }
 
+// NewLRUBytes creates an in memory cache that applies an LRU policy, constrained by the total cost of its entries rather than their number.
+//
+// The cost of each entry is computed by sizer. Entries costing more than maxBytes are not stored.
+func NewLRUBytes[T comparable, K any](maxBytes int, sizer Sizer[T, K]) *LRU[T, K] {
+	l := NewLRU[T, K](maxBytes)
+	l.sizer = sizer
+	return l
+}
+
 func (l *LRU[T, K]) Get(key T, opts ...Opt) (K, bool) {
 	o := getOpts(opts...)
 
@@ -68,24 +79,38 @@ func (l *LRU[T, K]) Get(key T, opts ...Opt) (K, bool) {
 
 func (l *LRU[T, K]) Put(key T, value K, opts ...Opt) bool {
 	o := getOpts(opts...)
+	defer o.notifyEvicted()
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	cost := l.cost(key, value)
 	ele, ok := l.hashMap[key]
+	if cost > l.threshold {
+		if ok {
+			l.unsafeRemove(ele)
+		}
+		queueEvicted(o, key, value)
+		return ok
+	}
+
 	if ok {
-		ele.Value.(*node[T, K]).value = value
+		n := ele.Value.(*node[T, K])
+		l.size += cost - n.cost
+		n.value = value
+		n.cost = cost
 		if !o.skipInc {
 			l.evictionQueue.MoveToBack(ele)
 		}
 	} else {
-		l.size += 1
+		l.size += cost
 		l.hashMap[key] = l.evictionQueue.PushBack(&node[T, K]{
 			key:   key,
 			value: value,
+			cost:  cost,
 		})
 	}
-	l.unsafeApplyPolicy()
+	l.unsafeApplyPolicy(o)
 
 	return ok
 }
@@ -95,9 +120,7 @@ func (l *LRU[T, K]) Evict(key T) bool {
 	defer l.mu.Unlock()
 
 	if ele, ok := l.hashMap[key]; ok {
-		l.size -= 1
-		l.evictionQueue.Remove(ele)
-		delete(l.hashMap, key)
+		l.unsafeRemove(ele)
 		return true
 	}
 	return false
@@ -118,21 +141,57 @@ func (l *LRU[T, K]) Values() map[T]K {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
-	values := make(map[T]K, l.size)
+	values := make(map[T]K, len(l.hashMap))
 	for k, element := range l.hashMap {
 		values[k] = element.Value.(*node[T, K]).value
 	}
 	return values
 }
 
+func (l *LRU[T, K]) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.hashMap)
+}
+
+// Bytes returns the total cost of the entries, as computed by the [Sizer].
+//
+// Returns 0 if the LRU was not created using [NewLRUBytes].
+func (l *LRU[T, K]) Bytes() int {
+	if l.sizer == nil {
+		return 0
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.size
+}
+
+// Entries cost 1 unless a sizer is set
+func (l *LRU[T, K]) cost(key T, value K) int {
+	if l.sizer == nil {
+		return 1
+	}
+	return l.sizer(key, value)
+}
+
 // Not concurrently safe!
-func (l *LRU[T, K]) unsafeApplyPolicy() {
+func (l *LRU[T, K]) unsafeApplyPolicy(o *opts) {
 	for l.size > l.threshold {
 		ele := l.evictionQueue.Front()
 		n := ele.Value.(*node[T, K])
-
-		l.size -= 1
-		l.evictionQueue.Remove(ele)
-		delete(l.hashMap, n.key)
+		l.unsafeRemove(ele)
+		queueEvicted(o, n.key, n.value)
 	}
 }
+
+// Not concurrently safe!
+func (l *LRU[T, K]) unsafeRemove(ele *list.Element) {
+	n := ele.Value.(*node[T, K])
+
+	l.size -= n.cost
+	l.evictionQueue.Remove(ele)
+	delete(l.hashMap, n.key)
+}
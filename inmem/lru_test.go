@@ -155,3 +155,63 @@ func BenchmarkLRUInc(b *testing.B) {
 		})
 	}
 }
+
+func TestLRUBytes(t *testing.T) {
+	lru := inmem.NewLRUBytes[string, string](10, func(key string, value string) int {
+		return len(key) + len(value)
+	})
+
+	lru.Put("a", "1234")         // 5 bytes
+	lru.Put("b", "1234")         // 10 bytes
+	lru.Get("a")                 // "b" is now the least recently used
+	lru.Put("c", "12")           // 13 bytes, evicts "b"
+	lru.Put("a", "12345")        // 9 bytes
+	lru.Put("d", "123456789012") // Larger than the budget, never stored
+
+	expected := map[string]string{"a": "12345", "c": "12"}
+	if got := lru.Values(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v\ngot %v", expected, got)
+	}
+	if got := lru.Bytes(); got != 9 {
+		t.Fatalf("expected %d bytes, got %d", 9, got)
+	}
+	if got := lru.Len(); got != 2 {
+		t.Fatalf("expected %d entries, got %d", 2, got)
+	}
+
+	// Growing an entry evicts the least recently used ones
+	lru.Put("c", "12345678")
+	expected = map[string]string{"c": "12345678"}
+	if got := lru.Values(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v\ngot %v", expected, got)
+	}
+
+	lru.Evict("c")
+	if got := lru.Bytes(); got != 0 {
+		t.Fatalf("expected %d bytes, got %d", 0, got)
+	}
+}
+
+func TestLRUOnEvict(t *testing.T) {
+	lru := inmem.NewLRU[string, string](2)
+
+	var evicted []string
+	onEvict := inmem.OnEvict(func(key string, value string) {
+		// Would deadlock if called while the LRU is locked
+		if _, ok := lru.Get(key); ok {
+			t.Errorf("expected key %q to be evicted before calling the eviction listener", key)
+		}
+		evicted = append(evicted, key+"="+value)
+	})
+
+	lru.Put("a", "1", onEvict)
+	lru.Put("b", "2", onEvict)
+	lru.Put("c", "3", onEvict)
+	lru.Put("d", "4")
+	lru.Evict("c")
+
+	expected := []string{"a=1"}
+	if !reflect.DeepEqual(evicted, expected) {
+		t.Fatalf("expected evictions %v\ngot %v", expected, evicted)
+	}
+}
@@ -4,6 +4,8 @@ type (
 	Opt  func(*opts)
 	opts struct {
 		skipInc bool
+		onEvict any
+		evicted []func()
 	}
 )
 
@@ -16,6 +18,13 @@ func SkipInc(skip bool) Opt {
 	return func(o *opts) { o.skipInc = skip }
 }
 
+// OnEvict is called for every entry evicted by the storage's policy to make room during a put, including the entry being put if it is not stored.
+//
+// It is called once the storage is unlocked. T and K must match the storage's key and value types, otherwise fn is never called.
+func OnEvict[T comparable, K any](fn func(key T, value K)) Opt {
+	return func(o *opts) { o.onEvict = fn }
+}
+
 func getOpts(options ...Opt) *opts {
 	defaultOpts := &opts{}
 	for _, o := range options {
@@ -23,3 +32,17 @@ func getOpts(options ...Opt) *opts {
 	}
 	return defaultOpts
 }
+
+// Queue a call to the eviction listener, so that it can be made once the storage is unlocked
+func queueEvicted[T comparable, K any](o *opts, key T, value K) {
+	if fn, ok := o.onEvict.(func(T, K)); ok {
+		o.evicted = append(o.evicted, func() { fn(key, value) })
+	}
+}
+
+// Call the eviction listener for every queued eviction
+func (o *opts) notifyEvicted() {
+	for _, fn := range o.evicted {
+		fn()
+	}
+}
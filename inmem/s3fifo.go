@@ -0,0 +1,225 @@
+package inmem
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+const s3FIFOMaxFreq = 3
+
+type (
+	// S3FIFO cache (simple, scalable, static FIFO queues)
+	//
+	// New entries are inserted in a small FIFO queue. Entries that were accessed while in the small queue are moved to the main FIFO queue,
+	// the others are evicted and their keys remembered in a ghost queue. Entries whose keys are in the ghost queue are inserted directly in the main queue.
+	// Entries of the main queue are reinserted instead of being evicted until they are no longer accessed.
+	//
+	// Accesses only update a counter, so concurrent gets don't contend for a write lock.
+	//
+	// The zero value is not ready for use. Refer to [NewS3FIFO] for the factory method.
+	S3FIFO[T comparable, K any] struct {
+		threshold int
+		smallCap  int
+		small     *list.List
+		main      *list.List
+		ghost     *list.List
+		ghostKeys map[T]*list.Element
+		hashMap   map[T]*list.Element
+		mu        *sync.RWMutex
+	}
+	s3FIFOEntry[T comparable, K any] struct {
+		key     T
+		value   K
+		freq    *atomic.Int32
+		inSmall bool
+	}
+)
+
+// NewS3FIFO creates an in memory cache that applies an S3-FIFO policy.
+//
+// 10% of the threshold is reserved for the small queue. The ghost queue remembers as many keys as the main queue can hold.
+func NewS3FIFO[T comparable, K any](threshold int) *S3FIFO[T, K] {
+	return &S3FIFO[T, K]{
+		threshold: threshold,
+		smallCap:  max(threshold/10, 1),
+		small:     list.New(),
+		main:      list.New(),
+		ghost:     list.New(),
+		ghostKeys: make(map[T]*list.Element),
+		hashMap:   make(map[T]*list.Element),
+		mu:        &sync.RWMutex{},
+	}
+}
+
+func (s *S3FIFO[T, K]) Get(key T, opts ...Opt) (K, bool) {
+	o := getOpts(opts...)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ele, ok := s.hashMap[key]
+	if !ok {
+		var empty K
+		return empty, false
+	}
+
+	entry := ele.Value.(*s3FIFOEntry[T, K])
+	if !o.skipInc {
+		entry.inc()
+	}
+	return entry.value, true
+}
+
+func (s *S3FIFO[T, K]) Put(key T, value K, opts ...Opt) bool {
+	o := getOpts(opts...)
+	defer o.notifyEvicted()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ele, ok := s.hashMap[key]; ok {
+		entry := ele.Value.(*s3FIFOEntry[T, K])
+		entry.value = value
+		if !o.skipInc {
+			entry.inc()
+		}
+		return true
+	}
+
+	if s.threshold <= 0 {
+		queueEvicted(o, key, value)
+		return false
+	}
+
+	for len(s.hashMap) >= s.threshold {
+		s.unsafeEvictOne(o)
+	}
+
+	entry := &s3FIFOEntry[T, K]{key: key, value: value, freq: &atomic.Int32{}}
+	if ghostEle, ok := s.ghostKeys[key]; ok {
+		s.ghost.Remove(ghostEle)
+		delete(s.ghostKeys, key)
+		s.hashMap[key] = s.main.PushBack(entry)
+	} else {
+		entry.inSmall = true
+		s.hashMap[key] = s.small.PushBack(entry)
+	}
+
+	return false
+}
+
+func (s *S3FIFO[T, K]) Evict(key T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ele, ok := s.hashMap[key]
+	if !ok {
+		return false
+	}
+
+	if ele.Value.(*s3FIFOEntry[T, K]).inSmall {
+		s.small.Remove(ele)
+	} else {
+		s.main.Remove(ele)
+	}
+	delete(s.hashMap, key)
+	return true
+}
+
+func (s *S3FIFO[T, K]) Inc(key T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if ele, ok := s.hashMap[key]; ok {
+		ele.Value.(*s3FIFOEntry[T, K]).inc()
+		return true
+	}
+	return false
+}
+
+func (s *S3FIFO[T, K]) Values() map[T]K {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	values := make(map[T]K, len(s.hashMap))
+	for k, ele := range s.hashMap {
+		values[k] = ele.Value.(*s3FIFOEntry[T, K]).value
+	}
+	return values
+}
+
+func (s *S3FIFO[T, K]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.hashMap)
+}
+
+// Not concurrently safe!
+func (s *S3FIFO[T, K]) unsafeEvictOne(o *opts) {
+	if s.small.Len() >= s.smallCap || s.main.Len() == 0 {
+		s.unsafeEvictSmall(o)
+	} else {
+		s.unsafeEvictMain(o)
+	}
+}
+
+// Not concurrently safe!
+// Moves the entries that were accessed to the main queue until an entry is evicted
+func (s *S3FIFO[T, K]) unsafeEvictSmall(o *opts) {
+	for s.small.Len() > 0 {
+		entry := s.small.Remove(s.small.Front()).(*s3FIFOEntry[T, K])
+		if entry.freq.Load() > 1 {
+			entry.inSmall = false
+			entry.freq.Store(0)
+			s.hashMap[entry.key] = s.main.PushBack(entry)
+			continue
+		}
+
+		delete(s.hashMap, entry.key)
+		s.unsafeAddGhost(entry.key)
+		queueEvicted(o, entry.key, entry.value)
+		return
+	}
+
+	// Every entry of the small queue was moved to the main queue
+	s.unsafeEvictMain(o)
+}
+
+// Not concurrently safe!
+// Reinserts the entries that were accessed until an entry is evicted
+func (s *S3FIFO[T, K]) unsafeEvictMain(o *opts) {
+	for s.main.Len() > 0 {
+		ele := s.main.Front()
+		entry := ele.Value.(*s3FIFOEntry[T, K])
+		if freq := entry.freq.Load(); freq > 0 {
+			entry.freq.Store(freq - 1)
+			s.main.MoveToBack(ele)
+			continue
+		}
+
+		s.main.Remove(ele)
+		delete(s.hashMap, entry.key)
+		queueEvicted(o, entry.key, entry.value)
+		return
+	}
+}
+
+// Not concurrently safe!
+func (s *S3FIFO[T, K]) unsafeAddGhost(key T) {
+	s.ghostKeys[key] = s.ghost.PushBack(key)
+	for s.ghost.Len() > s.threshold-s.smallCap {
+		delete(s.ghostKeys, s.ghost.Remove(s.ghost.Front()).(T))
+	}
+}
+
+// Increment the access frequency, capped to a small value
+func (e *s3FIFOEntry[T, K]) inc() {
+	for {
+		freq := e.freq.Load()
+		if freq >= s3FIFOMaxFreq || e.freq.CompareAndSwap(freq, freq+1) {
+			return
+		}
+	}
+}
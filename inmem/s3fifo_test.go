@@ -0,0 +1,85 @@
+package inmem_test
+
+import (
+	"github.com/MysteriousPotato/nitecache/inmem"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestS3FIFO(t *testing.T) {
+	s3fifo := inmem.NewS3FIFO[int, int](10)
+	for i := 0; i < 10; i++ {
+		if exists := s3fifo.Put(i, i); exists {
+			t.Fatalf("Expected exists %t, got %t for put operation", false, exists)
+		}
+	}
+
+	// Accessed entries are moved to the main queue instead of being evicted
+	for i := 0; i < 2; i++ {
+		s3fifo.Get(0)
+		s3fifo.Get(1)
+	}
+	s3fifo.Put(10, 10)
+	s3fifo.Put(11, 11)
+
+	values := s3fifo.Values()
+	if len(values) != 10 {
+		t.Fatalf("Expected %d entries, got %v", 10, values)
+	}
+	for _, key := range []int{0, 1, 10, 11} {
+		if _, ok := values[key]; !ok {
+			t.Fatalf("Expected key %d to be retained, got %v", key, values)
+		}
+	}
+
+	// Keys evicted recently are remembered and inserted directly in the main queue
+	if _, ok := values[2]; ok {
+		t.Fatalf("Expected key %d to be evicted, got %v", 2, values)
+	}
+	s3fifo.Put(2, 2)
+	for i := 12; i < 20; i++ {
+		s3fifo.Put(i, i)
+	}
+	if _, ok := s3fifo.Get(2); !ok {
+		t.Fatalf("Expected key %d to be retained, got %v", 2, s3fifo.Values())
+	}
+
+	if exists := s3fifo.Evict(2); !exists {
+		t.Fatalf("Expected exists %t, got %t for evict operation", true, exists)
+	}
+	if exists := s3fifo.Evict(2); exists {
+		t.Fatalf("Expected exists %t, got %t for evict operation", false, exists)
+	}
+	if l := s3fifo.Len(); l != 9 {
+		t.Fatalf("Expected %d entries, got %d", 9, l)
+	}
+}
+
+func TestS3FIFOConcurrentAccess(t *testing.T) {
+	goroutinesCount := 100
+	iterations := 1000
+
+	s3fifo := inmem.NewS3FIFO[int, int](128)
+	wg := sync.WaitGroup{}
+
+	wg.Add(goroutinesCount)
+	for i := 0; i < goroutinesCount; i++ {
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < iterations; j++ {
+				s3fifo.Put(j, j)
+				s3fifo.Get(j)
+				s3fifo.Inc(j)
+				s3fifo.Evict(j)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if values := s3fifo.Values(); !reflect.DeepEqual(values, map[int]int{}) {
+		t.Fatalf("Expected no values, got %v", values)
+	}
+}
@@ -2,6 +2,9 @@ package inmem
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/MysteriousPotato/go-lockable"
@@ -10,25 +13,93 @@ import (
 // Getter Type used for auto cache filling
 type Getter[K comparable, V any] func(ctx context.Context, key K) (V, time.Duration, error)
 
+// ErrNotExist can be returned (or wrapped) by a [Getter] to signal that no value exists for the key.
+//
+// Refer to [WithNegativeTTL] for caching the absence of value.
+var (
+	ErrNotExist = errors.New("value does not exist")
+	// ErrNotEnumerable means the storage does not implement Values, so its items can't be listed. Refer to [Storage].
+	ErrNotEnumerable = errors.New("storage can't list its items")
+)
+
 type (
 	StoreOpt[K comparable, V any] func(*Store[K, V])
 	Store[K comparable, V any]    struct {
-		lock     lockable.Lockable[K]
-		getter   Getter[K, V]
-		internal Storage[K, V]
+		lock                 lockable.Lockable[K]
+		getter               Getter[K, V]
+		internal             Storage[K, V]
+		version              *atomic.Uint64
+		refreshAhead         float64
+		staleWhileRevalidate time.Duration
+		negativeTTL          time.Duration
+		refreshes            *refreshes[K]
+		sweepInterval        time.Duration
+		expirations          *expirationQueue[K]
+		expired              *atomic.Int64
+		stopSweep            func()
+		onEvict              func(key K, item Item[V], reason EvictReason)
 	}
+	// Item is a value stored in a [Store].
+	//
+	// Version is assigned by the [Store] when the item is written with a Version of 0.
+	// Items written with a non-zero Version (i.e. replicated from another store) keep it.
 	Item[T any] struct {
-		Expire time.Time
-		Value  T
+		Expire  time.Time
+		Value   T
+		Version uint64
+		// Absent marks the cached absence of value for the key. Refer to [WithNegativeTTL].
+		Absent bool
+		// TTL the item was created with, used for refreshing ahead of expiration.
+		// Unknown for items that were not created by a Store.
+		ttl time.Duration
+	}
+	// Keys currently being refreshed in the background
+	refreshes[K comparable] struct {
+		mu   sync.Mutex
+		keys map[K]struct{}
+	}
+	// Item removed during an operation, reported once the operation's key is unlocked
+	eviction[K comparable, V any] struct {
+		key    K
+		item   Item[V]
+		reason EvictReason
 	}
 )
 
+// EvictReason describes why an item was removed from a [Store]. Refer to [WithOnEvict].
+type EvictReason int
+
+const (
+	// EvictCapacity means the item was evicted by the storage's policy to make room for other items.
+	EvictCapacity EvictReason = iota
+	// EvictExpired means the item expired before being removed, either by the expiration sweep or by the storage's policy.
+	EvictExpired
+	// EvictExplicit means the item was removed using [Store.Evict] or [Store.EvictAll].
+	EvictExplicit
+)
+
+// Sizer returns the cost of an entry, in bytes. Refer to [NewLRUBytes] and [NewLFUBytes].
+type Sizer[K comparable, V any] func(key K, value V) int
+
+// Storage holds the items of a [Store].
+//
+// Storages should also implement Values() map[K]Item[V] and Len() int, which are used by [Store.Values] and [Store.Len].
+// Without Values, the items of the store can't be listed, i.e. for rebalancing or taking snapshots.
 type Storage[K comparable, V any] interface {
 	Put(key K, value Item[V], opt ...Opt) bool
 	Evict(key K) bool
 	Get(key K, opt ...Opt) (Item[V], bool)
 }
 
+type (
+	enumerableStorage[K comparable, V any] interface {
+		Values() map[K]Item[V]
+	}
+	countedStorage interface {
+		Len() int
+	}
+)
+
 func WithStorage[K comparable, V any](storage Storage[K, V]) StoreOpt[K, V] {
 	return func(s *Store[K, V]) {
 		s.internal = storage
@@ -41,9 +112,50 @@ func WithGetter[K comparable, V any](getter Getter[K, V]) StoreOpt[K, V] {
 	}
 }
 
+// WithRefreshAhead refreshes items in the background using the getter once they are accessed after the given fraction of their TTL has elapsed.
+//
+// Fraction must be between 0 and 1, exclusively. Otherwise, items are not refreshed ahead of their expiration.
+//
+// Only items created by the store (i.e. using [Store.NewItem] or the getter) can be refreshed ahead.
+func WithRefreshAhead[K comparable, V any](fraction float64) StoreOpt[K, V] {
+	return func(s *Store[K, V]) {
+		s.refreshAhead = fraction
+	}
+}
+
+// WithStaleWhileRevalidate returns expired items accessed within the given window after their expiration,
+// instead of blocking until the getter returns, while a single background refresh runs.
+func WithStaleWhileRevalidate[K comparable, V any](window time.Duration) StoreOpt[K, V] {
+	return func(s *Store[K, V]) {
+		s.staleWhileRevalidate = window
+	}
+}
+
+// WithNegativeTTL caches the absence of value for the given duration when the getter returns [ErrNotExist].
+//
+// Cached absences are returned as items marked [Item.Absent], without calling the getter until they expire.
+func WithNegativeTTL[K comparable, V any](ttl time.Duration) StoreOpt[K, V] {
+	return func(s *Store[K, V]) {
+		s.negativeTTL = ttl
+	}
+}
+
+// WithOnEvict calls fn for every item removed from the store, once the key and the storage are unlocked.
+//
+// Items replaced by a put, items removed using [Store.Remove] and cached absences (see [WithNegativeTTL]) are not reported.
+// Unless using the expiration sweep (see [WithExpirationSweep]), expired items are only reported once evicted by the storage's policy.
+func WithOnEvict[K comparable, V any](fn func(key K, item Item[V], reason EvictReason)) StoreOpt[K, V] {
+	return func(s *Store[K, V]) {
+		s.onEvict = fn
+	}
+}
+
 func NewStore[K comparable, V any](opts ...StoreOpt[K, V]) *Store[K, V] {
 	s := &Store[K, V]{
-		lock: lockable.New[K](),
+		lock:      lockable.New[K](),
+		version:   &atomic.Uint64{},
+		refreshes: &refreshes[K]{keys: map[K]struct{}{}},
+		expired:   &atomic.Int64{},
 	}
 
 	for _, opt := range opts {
@@ -54,10 +166,18 @@ func NewStore[K comparable, V any](opts ...StoreOpt[K, V]) *Store[K, V] {
 		s.internal = NewCache[K, Item[V]]()
 	}
 
+	if s.sweepInterval > 0 {
+		s.expirations = newExpirationQueue[K]()
+		s.startSweep()
+	}
+
 	return s
 }
 
 func (s Store[K, V]) Get(ctx context.Context, key K) (Item[V], bool, error) {
+	var evicted []eviction[K, V]
+	defer s.notifyEvicted(&evicted)
+
 	var unlocked bool
 	s.lock.RLockKey(key)
 	defer func() {
@@ -67,6 +187,10 @@ func (s Store[K, V]) Get(ctx context.Context, key K) (Item[V], bool, error) {
 	}()
 
 	itm, hit := s.internal.Get(key)
+	if s.getter != nil && hit && (s.isStale(itm) || s.shouldRefreshAhead(itm)) {
+		s.refreshInBackground(ctx, key, itm.Version)
+		return itm, hit, nil
+	}
 	if s.getter != nil && (!hit || itm.IsExpired()) {
 		s.lock.RUnlockKey(key)
 		unlocked = true
@@ -74,7 +198,7 @@ func (s Store[K, V]) Get(ctx context.Context, key K) (Item[V], bool, error) {
 		s.lock.LockKey(key)
 		defer s.lock.UnlockKey(key)
 
-		itm, err := s.unsafeCacheAside(ctx, key)
+		itm, err := s.unsafeCacheAside(ctx, key, &evicted)
 		if err != nil {
 			return itm, false, err
 		}
@@ -84,18 +208,81 @@ func (s Store[K, V]) Get(ctx context.Context, key K) (Item[V], bool, error) {
 	return itm, hit, nil
 }
 
-func (s Store[K, V]) Put(key K, item Item[V]) {
+// Put stores the item and returns it with its version.
+func (s Store[K, V]) Put(key K, item Item[V]) Item[V] {
+	var evicted []eviction[K, V]
+	defer s.notifyEvicted(&evicted)
+
+	s.lock.LockKey(key)
+	defer s.lock.UnlockKey(key)
+
+	return s.unsafePut(key, item, &evicted)
+}
+
+// CompareAndSwap stores the item only if the version of the current item matches the expected version.
+//
+// A missing or expired item has a version of 0.
+//
+// Returns the stored item, or the current item if the versions did not match.
+func (s Store[K, V]) CompareAndSwap(key K, expectedVersion uint64, item Item[V]) (Item[V], bool) {
+	var evicted []eviction[K, V]
+	defer s.notifyEvicted(&evicted)
+
 	s.lock.LockKey(key)
 	defer s.lock.UnlockKey(key)
 
-	s.internal.Put(key, item)
+	current := s.unsafeCurrent(key)
+	if current.Version != expectedVersion {
+		return current, false
+	}
+
+	return s.unsafePut(key, item, &evicted), true
+}
+
+// Version returns the version of the current item, or 0 if it is missing or expired.
+func (s Store[K, V]) Version(key K) uint64 {
+	s.lock.RLockKey(key)
+	defer s.lock.RUnlockKey(key)
+
+	return s.unsafeCurrent(key).Version
+}
+
+// PutIfAbsent stores the item only if no unexpired item is already stored for the given key.
+//
+// Returns whether the item was stored.
+func (s Store[K, V]) PutIfAbsent(key K, item Item[V]) bool {
+	var evicted []eviction[K, V]
+	defer s.notifyEvicted(&evicted)
+
+	s.lock.LockKey(key)
+	defer s.lock.UnlockKey(key)
+
+	if itm, ok := s.internal.Get(key, SkipInc(true)); ok && !itm.IsExpired() && !itm.Absent {
+		return false
+	}
+
+	s.unsafePut(key, item, &evicted)
+	return true
 }
 
 func (s Store[K, V]) Evict(key K) {
+	var evicted []eviction[K, V]
+	defer s.notifyEvicted(&evicted)
+
 	s.lock.LockKey(key)
 	defer s.lock.UnlockKey(key)
 
-	s.internal.Evict(key)
+	if item, ok := s.unsafeRemove(key); ok {
+		evicted = append(evicted, eviction[K, V]{key: key, item: item, reason: EvictExplicit})
+	}
+}
+
+// Remove behaves like [Store.Evict], but returns the removed item instead of reporting it to the eviction listener.
+func (s Store[K, V]) Remove(key K) (Item[V], bool) {
+	s.lock.LockKey(key)
+	defer s.lock.UnlockKey(key)
+
+	return s.unsafeRemove(key)
 }
 
 func (s Store[K, V]) EvictAll(keys []K) {
@@ -110,6 +297,9 @@ func (s Store[K, V]) Update(
 	args []byte,
 	fn func(context.Context, V, []byte) (V, time.Duration, error),
 ) (Item[V], error) {
+	var evicted []eviction[K, V]
+	defer s.notifyEvicted(&evicted)
+
 	s.lock.LockKey(key)
 	defer s.lock.UnlockKey(key)
 
@@ -118,7 +308,7 @@ func (s Store[K, V]) Update(
 	if !ok && s.getter != nil {
 		skipInc = true
 		var err error
-		if oldItem, err = s.unsafeCacheAside(ctx, key); err != nil {
+		if oldItem, err = s.unsafeCacheAside(ctx, key, &evicted); err != nil {
 			return Item[V]{}, err
 		}
 	}
@@ -128,10 +318,42 @@ func (s Store[K, V]) Update(
 		return Item[V]{}, err
 	}
 
-	newItem := s.NewItem(newValue, ttl)
-	s.internal.Put(key, newItem, SkipInc(skipInc))
+	return s.unsafePut(key, s.NewItem(newValue, ttl), &evicted, SkipInc(skipInc)), nil
+}
+
+// Values returns a copy of every item currently stored, including expired ones.
+//
+// Returns nil if the storage does not implement Values. Refer to [Store.Enumerable].
+func (s Store[K, V]) Values() map[K]Item[V] {
+	if enumerable, ok := s.internal.(enumerableStorage[K, V]); ok {
+		return enumerable.Values()
+	}
+	return nil
+}
+
+// Enumerable reports whether the storage implements Values, so that its items can be listed using [Store.Values].
+func (s Store[K, V]) Enumerable() bool {
+	_, ok := s.internal.(enumerableStorage[K, V])
+	return ok
+}
+
+// Len returns the number of items currently stored, including expired ones.
+//
+// If the storage does not implement Len, falls back to counting its values, or 0 if it does not implement Values either.
+func (s Store[K, V]) Len() int {
+	if counted, ok := s.internal.(countedStorage); ok {
+		return counted.Len()
+	}
+	return len(s.Values())
+}
 
-	return newItem, nil
+// Bytes returns the total cost of the items currently stored, if the storage is constrained by a byte budget.
+// Otherwise, returns 0.
+func (s Store[K, V]) Bytes() int {
+	if sized, ok := s.internal.(interface{ Bytes() int }); ok {
+		return sized.Bytes()
+	}
+	return 0
 }
 
 func (s Store[K, V]) NewItem(value V, ttl time.Duration) Item[V] {
@@ -143,20 +365,140 @@ func (s Store[K, V]) NewItem(value V, ttl time.Duration) Item[V] {
 	return Item[V]{
 		Expire: exp,
 		Value:  value,
+		ttl:    ttl,
+	}
+}
+
+// Whether the item expired, but can still be returned while it is being refreshed
+func (s Store[K, V]) isStale(item Item[V]) bool {
+	return item.IsExpired() && time.Since(item.Expire) < s.staleWhileRevalidate
+}
+
+func (s Store[K, V]) shouldRefreshAhead(item Item[V]) bool {
+	if s.refreshAhead <= 0 || s.refreshAhead >= 1 || item.ttl <= 0 || item.IsExpired() {
+		return false
+	}
+	return time.Until(item.Expire) < time.Duration(float64(item.ttl)*(1-s.refreshAhead))
+}
+
+// Call the getter in the background, unless a refresh is already running for the key.
+//
+// The key is not locked while calling the getter, so that the current item can still be read in the meantime.
+// The result is discarded if the item was modified or evicted during the refresh.
+func (s Store[K, V]) refreshInBackground(ctx context.Context, key K, version uint64) {
+	if !s.refreshes.start(key) {
+		return
 	}
+
+	go func() {
+		defer s.refreshes.done(key)
+
+		var evicted []eviction[K, V]
+		defer s.notifyEvicted(&evicted)
+
+		item, err := s.callGetter(context.WithoutCancel(ctx), key)
+		if err != nil {
+			return
+		}
+
+		s.lock.LockKey(key)
+		defer s.lock.UnlockKey(key)
+
+		if current, ok := s.internal.Get(key, SkipInc(true)); !ok || current.Version != version {
+			return
+		}
+		s.unsafePut(key, item, &evicted, SkipInc(true))
+	}()
 }
 
 // Make sure to lock the key before using this
-func (s Store[K, V]) unsafeCacheAside(ctx context.Context, key K) (Item[V], error) {
+func (s Store[K, V]) unsafeCacheAside(ctx context.Context, key K, evicted *[]eviction[K, V]) (Item[V], error) {
+	item, err := s.callGetter(ctx, key)
+	if err != nil {
+		return Item[V]{}, err
+	}
+
+	return s.unsafePut(key, item, evicted, SkipInc(true)), nil
+}
+
+// Call the getter, turning [ErrNotExist] into an absent item if negative caching is enabled
+func (s Store[K, V]) callGetter(ctx context.Context, key K) (Item[V], error) {
 	v, ttl, err := s.getter(ctx, key)
+	if s.negativeTTL > 0 && errors.Is(err, ErrNotExist) {
+		item := s.NewItem(s.getEmptyValue(), s.negativeTTL)
+		item.Absent = true
+		return item, nil
+	}
 	if err != nil {
 		return Item[V]{}, err
 	}
 
-	newItem := s.NewItem(v, ttl)
-	s.internal.Put(key, newItem, SkipInc(true))
+	return s.NewItem(v, ttl), nil
+}
+
+// Assign a new version to the item if needed, otherwise make sure the next assigned versions will be greater than the item's.
+// Items evicted by the storage's policy are appended to evicted.
+//
+// Make sure to lock the key before using this
+func (s Store[K, V]) unsafePut(key K, item Item[V], evicted *[]eviction[K, V], opts ...Opt) Item[V] {
+	if item.Version == 0 {
+		item.Version = s.version.Add(1)
+	} else {
+		for current := s.version.Load(); current < item.Version; current = s.version.Load() {
+			if s.version.CompareAndSwap(current, item.Version) {
+				break
+			}
+		}
+	}
+
+	if s.onEvict != nil {
+		opts = append(opts, OnEvict(func(key K, item Item[V]) {
+			reason := EvictCapacity
+			if item.IsExpired() {
+				reason = EvictExpired
+			}
+			*evicted = append(*evicted, eviction[K, V]{key: key, item: item, reason: reason})
+		}))
+	}
+
+	s.internal.Put(key, item, opts...)
+	s.unsafeSchedule(key, item)
+	return item
+}
 
-	return newItem, nil
+// Return the current item, or an empty item if it is missing, expired or absent.
+//
+// Make sure to lock the key before using this
+func (s Store[K, V]) unsafeCurrent(key K) Item[V] {
+	current, ok := s.internal.Get(key, SkipInc(true))
+	if !ok || current.IsExpired() || current.Absent {
+		return Item[V]{}
+	}
+	return current
+}
+
+// Make sure to lock the key before using this
+func (s Store[K, V]) unsafeRemove(key K) (Item[V], bool) {
+	item, ok := s.internal.Get(key, SkipInc(true))
+	if !s.internal.Evict(key) {
+		ok = false
+	}
+	if s.expirations != nil {
+		s.expirations.remove(key)
+	}
+	return item, ok
+}
+
+// Report evictions to the eviction listener. Make sure every lock is released before using this.
+func (s Store[K, V]) notifyEvicted(evicted *[]eviction[K, V]) {
+	if s.onEvict == nil {
+		return
+	}
+	for _, e := range *evicted {
+		if !e.item.Absent {
+			s.onEvict(e.key, e.item, e.reason)
+		}
+	}
 }
 
 func (s Store[K, V]) getEmptyValue() V {
@@ -164,6 +506,25 @@ func (s Store[K, V]) getEmptyValue() V {
 	return v
 }
 
+// Returns false if the key is already being refreshed
+func (r *refreshes[K]) start(key K) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.keys[key]; ok {
+		return false
+	}
+	r.keys[key] = struct{}{}
+	return true
+}
+
+func (r *refreshes[K]) done(key K) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.keys, key)
+}
+
 func (i Item[V]) IsExpired() bool {
 	return !i.Expire.IsZero() && i.Expire.Before(time.Now())
 }
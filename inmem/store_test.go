@@ -4,7 +4,11 @@ import (
 	"context"
 	"github.com/MysteriousPotato/nitecache/inmem"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -105,3 +109,293 @@ func TestStoreTTL(t *testing.T) {
 		}
 	}
 }
+
+func TestStoreCompareAndSwap(t *testing.T) {
+	s := inmem.NewStore[string, string]()
+
+	if _, swapped := s.CompareAndSwap("1", 1, s.NewItem("test", 0)); swapped {
+		t.Fatal("expected swap to fail on missing key with non-zero version")
+	}
+
+	item, swapped := s.CompareAndSwap("1", 0, s.NewItem("test", 0))
+	if !swapped {
+		t.Fatal("expected swap to succeed on missing key with version 0")
+	}
+
+	current, swapped := s.CompareAndSwap("1", item.Version+1, s.NewItem("stale", 0))
+	if swapped || current.Value != "test" || current.Version != item.Version {
+		t.Fatalf("expected swap to fail and return current item %v, got: %v", item, current)
+	}
+
+	newItem, swapped := s.CompareAndSwap("1", item.Version, s.NewItem("new", 0))
+	if !swapped || newItem.Version <= item.Version {
+		t.Fatalf("expected swap to succeed with a greater version than %d, got: %v", item.Version, newItem)
+	}
+
+	// Replicated items keep their version and subsequent versions must be greater
+	replicated := s.Put("2", inmem.Item[string]{Value: "replicated", Version: 100})
+	if replicated.Version != 100 {
+		t.Fatalf("expected version 100, got: %d", replicated.Version)
+	}
+	if next := s.Put("3", s.NewItem("next", 0)); next.Version <= 100 {
+		t.Fatalf("expected version greater than 100, got: %d", next.Version)
+	}
+}
+
+func TestStoreRefreshAhead(t *testing.T) {
+	var calls atomic.Int64
+	s := inmem.NewStore(
+		inmem.WithGetter[string, string](func(_ context.Context, _ string) (string, time.Duration, error) {
+			return strconv.Itoa(int(calls.Add(1))), time.Millisecond * 200, nil
+		}),
+		inmem.WithRefreshAhead[string, string](0.5),
+	)
+	ctx := context.Background()
+
+	if item, _, err := s.Get(ctx, "1"); err != nil || item.Value != "1" {
+		t.Fatalf("expected value %q, got: %q, err: %v", "1", item.Value, err)
+	}
+
+	// Past half of the TTL, the current value is returned while refreshing in the background
+	time.Sleep(time.Millisecond * 120)
+	if item, _, err := s.Get(ctx, "1"); err != nil || item.Value != "1" {
+		t.Fatalf("expected value %q, got: %q, err: %v", "1", item.Value, err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		item, _, err := s.Get(ctx, "1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.Value == "2" && !item.IsExpired() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected value to be refreshed, got: %q", item.Value)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStoreStaleWhileRevalidate(t *testing.T) {
+	var calls atomic.Int64
+	release := make(chan struct{})
+	s := inmem.NewStore(
+		inmem.WithGetter[string, string](func(_ context.Context, _ string) (string, time.Duration, error) {
+			n := calls.Add(1)
+			if n > 1 {
+				<-release
+			}
+			return strconv.Itoa(int(n)), time.Millisecond * 10, nil
+		}),
+		inmem.WithStaleWhileRevalidate[string, string](time.Hour),
+	)
+	ctx := context.Background()
+
+	if _, _, err := s.Get(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 20)
+
+	// Expired values are returned without waiting for the getter, which is called only once
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if item, _, err := s.Get(ctx, "1"); err != nil || item.Value != "1" {
+				t.Errorf("expected stale value %q, got: %q, err: %v", "1", item.Value, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		item, _, err := s.Get(ctx, "1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.Value != "1" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected value to be refreshed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if n := calls.Load(); n < 2 || n > 3 {
+		t.Fatalf("expected a single background refresh, got %d getter calls", n)
+	}
+}
+
+func TestStoreNegativeTTL(t *testing.T) {
+	var calls atomic.Int64
+	s := inmem.NewStore(
+		inmem.WithGetter[string, string](func(_ context.Context, _ string) (string, time.Duration, error) {
+			calls.Add(1)
+			return "", 0, inmem.ErrNotExist
+		}),
+		inmem.WithNegativeTTL[string, string](time.Millisecond*50),
+	)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		item, _, err := s.Get(ctx, "1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !item.Absent {
+			t.Fatalf("expected absent item, got: %v", item)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected getter to be called once, got: %d", n)
+	}
+
+	time.Sleep(time.Millisecond * 60)
+	if _, _, err := s.Get(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("expected getter to be called again after the negative TTL, got: %d calls", n)
+	}
+
+	// Cached absences have no version as far as writes are concerned
+	if _, swapped := s.CompareAndSwap("1", 0, s.NewItem("test", 0)); !swapped {
+		t.Fatal("expected swap to succeed on absent key with version 0")
+	}
+	if item, _, err := s.Get(ctx, "1"); err != nil || item.Absent || item.Value != "test" {
+		t.Fatalf("expected value %q, got: %v, err: %v", "test", item, err)
+	}
+}
+
+func TestStoreExpirationSweep(t *testing.T) {
+	s := inmem.NewStore(inmem.WithExpirationSweep[string, string](time.Millisecond))
+	defer s.Close()
+
+	s.Put("expired", s.NewItem("1", time.Millisecond*10))
+	s.Put("renewed", s.NewItem("2", time.Millisecond*10))
+	s.Put("renewed", s.NewItem("2", time.Hour))
+	s.Put("persisted", s.NewItem("3", time.Millisecond*10))
+	s.Put("persisted", s.NewItem("3", 0))
+	s.Put("evicted", s.NewItem("4", time.Millisecond*10))
+	s.Evict("evicted")
+
+	deadline := time.Now().Add(time.Second)
+	for s.Expired() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected expired items to be swept")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(time.Millisecond * 20)
+
+	if n := s.Expired(); n != 1 {
+		t.Fatalf("expected %d expired item, got: %d", 1, n)
+	}
+	expected := []string{"persisted", "renewed"}
+	var got []string
+	for key := range s.Values() {
+		got = append(got, key)
+	}
+	slices.Sort(got)
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected keys %v, got: %v", expected, got)
+	}
+}
+
+func TestStoreOnEvict(t *testing.T) {
+	type evicted struct {
+		key    string
+		value  string
+		reason inmem.EvictReason
+	}
+
+	var got []evicted
+	var s *inmem.Store[string, string]
+	s = inmem.NewStore(
+		inmem.WithStorage[string, string](inmem.NewLRU[string, inmem.Item[string]](2)),
+		inmem.WithOnEvict(func(key string, item inmem.Item[string], reason inmem.EvictReason) {
+			// Would deadlock if called while the key or the storage is locked
+			if _, ok, _ := s.Get(context.Background(), key); ok {
+				t.Errorf("expected key %q to be evicted before calling the eviction listener", key)
+			}
+			got = append(got, evicted{key: key, value: item.Value, reason: reason})
+		}),
+	)
+
+	s.Put("a", s.NewItem("1", time.Millisecond))
+	time.Sleep(time.Millisecond * 5)
+	s.Put("b", s.NewItem("2", 0))
+	s.Put("c", s.NewItem("3", 0)) // Evicts the expired "a"
+	s.Put("d", s.NewItem("4", 0)) // Evicts "b"
+	s.Put("d", s.NewItem("5", 0)) // Replacements are not evictions
+	s.Evict("c")
+	if item, ok := s.Remove("d"); !ok || item.Value != "5" {
+		t.Fatalf("expected to remove %q, got: %q", "5", item.Value)
+	}
+
+	expected := []evicted{
+		{key: "a", value: "1", reason: inmem.EvictExpired},
+		{key: "b", value: "2", reason: inmem.EvictCapacity},
+		{key: "c", value: "3", reason: inmem.EvictExplicit},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected evictions %+v\ngot %+v", expected, got)
+	}
+}
+
+// minimalStorage only implements the methods required by inmem.Storage
+type minimalStorage struct {
+	mu    sync.Mutex
+	items map[string]inmem.Item[string]
+}
+
+func (m *minimalStorage) Put(key string, value inmem.Item[string], _ ...inmem.Opt) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.items[key]
+	m.items[key] = value
+	return ok
+}
+
+func (m *minimalStorage) Evict(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.items[key]
+	delete(m.items, key)
+	return ok
+}
+
+func (m *minimalStorage) Get(key string, _ ...inmem.Opt) (inmem.Item[string], bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[key]
+	return item, ok
+}
+
+func TestStoreMinimalStorage(t *testing.T) {
+	s := inmem.NewStore(inmem.WithStorage[string, string](&minimalStorage{items: map[string]inmem.Item[string]{}}))
+
+	s.Put("1", s.NewItem("test", 0))
+	if item, hit, err := s.Get(context.Background(), "1"); err != nil || !hit || item.Value != "test" {
+		t.Fatalf("expected value %q, got: %q, hit: %v, err: %v", "test", item.Value, hit, err)
+	}
+
+	if s.Enumerable() {
+		t.Fatal("expected store not to be enumerable")
+	}
+	if values := s.Values(); values != nil {
+		t.Fatalf("expected no values, got: %v", values)
+	}
+	if n := s.Len(); n != 0 {
+		t.Fatalf("expected length %d, got: %d", 0, n)
+	}
+}
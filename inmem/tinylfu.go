@@ -0,0 +1,308 @@
+package inmem
+
+import (
+	"container/list"
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"math/bits"
+	"sync"
+)
+
+const (
+	tinyLFUWindow = iota
+	tinyLFUProbation
+	tinyLFUProtected
+)
+
+type (
+	// TinyLFU cache (W-TinyLFU admission policy)
+	//
+	// New entries are first stored in a small LRU window. Entries evicted from the window are only admitted into the main LRU
+	// if they were accessed more frequently than the entry they would replace.
+	// Access frequencies are estimated using a count-min sketch, which is periodically halved so that old accesses fade away.
+	//
+	// The main LRU is segmented: entries accessed again while on probation are promoted to a protected segment.
+	//
+	// The zero value is not ready for use. Refer to [NewTinyLFU] for the factory method.
+	TinyLFU[T comparable, K any] struct {
+		threshold    int
+		windowCap    int
+		protectedCap int
+		window       *list.List
+		probation    *list.List
+		protected    *list.List
+		hashMap      map[T]*list.Element
+		sketch       *countMinSketch
+		seed         maphash.Seed
+		mu           *sync.Mutex
+	}
+	tinyLFUEntry[T comparable, K any] struct {
+		key     T
+		value   K
+		segment int
+	}
+	// countMinSketch estimates access frequencies using 4 rows of 4-bit counters.
+	countMinSketch struct {
+		rows       [4][]uint8
+		mask       uint64
+		additions  int
+		sampleSize int
+	}
+)
+
+// NewTinyLFU creates an in memory cache that applies a W-TinyLFU policy.
+//
+// 1% of the threshold is reserved for the window, the remaining being split between the probation (20%) and protected (80%) segments.
+func NewTinyLFU[T comparable, K any](threshold int) *TinyLFU[T, K] {
+	windowCap := max(threshold/100, 1)
+	mainCap := max(threshold-windowCap, 0)
+
+	return &TinyLFU[T, K]{
+		threshold:    threshold,
+		windowCap:    windowCap,
+		protectedCap: mainCap * 8 / 10,
+		window:       list.New(),
+		probation:    list.New(),
+		protected:    list.New(),
+		hashMap:      make(map[T]*list.Element),
+		sketch:       newCountMinSketch(threshold),
+		seed:         maphash.MakeSeed(),
+		mu:           &sync.Mutex{},
+	}
+}
+
+func (l *TinyLFU[T, K]) Get(key T, opts ...Opt) (K, bool) {
+	o := getOpts(opts...)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Misses are recorded as well, so that frequently requested keys are admitted once they are stored
+	if !o.skipInc {
+		l.sketch.increment(l.hash(key))
+	}
+
+	ele, ok := l.hashMap[key]
+	if !ok {
+		var empty K
+		return empty, false
+	}
+
+	if !o.skipInc {
+		l.unsafeTouch(ele)
+	}
+	return ele.Value.(*tinyLFUEntry[T, K]).value, true
+}
+
+func (l *TinyLFU[T, K]) Put(key T, value K, opts ...Opt) bool {
+	o := getOpts(opts...)
+	defer o.notifyEvicted()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !o.skipInc {
+		l.sketch.increment(l.hash(key))
+	}
+
+	if ele, ok := l.hashMap[key]; ok {
+		ele.Value.(*tinyLFUEntry[T, K]).value = value
+		if !o.skipInc {
+			l.unsafeTouch(ele)
+		}
+		return true
+	}
+
+	if l.threshold <= 0 {
+		queueEvicted(o, key, value)
+		return false
+	}
+
+	l.hashMap[key] = l.window.PushBack(&tinyLFUEntry[T, K]{
+		key:     key,
+		value:   value,
+		segment: tinyLFUWindow,
+	})
+	l.unsafeApplyPolicy(o)
+
+	return false
+}
+
+func (l *TinyLFU[T, K]) Evict(key T) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if ele, ok := l.hashMap[key]; ok {
+		l.unsafeRemove(ele)
+		return true
+	}
+	return false
+}
+
+func (l *TinyLFU[T, K]) Inc(key T) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sketch.increment(l.hash(key))
+	if ele, ok := l.hashMap[key]; ok {
+		l.unsafeTouch(ele)
+		return true
+	}
+	return false
+}
+
+func (l *TinyLFU[T, K]) Values() map[T]K {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	values := make(map[T]K, len(l.hashMap))
+	for k, ele := range l.hashMap {
+		values[k] = ele.Value.(*tinyLFUEntry[T, K]).value
+	}
+	return values
+}
+
+func (l *TinyLFU[T, K]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.hashMap)
+}
+
+// Not concurrently safe!
+// Moves the entry to the back of its segment, or promotes it to the protected segment if it was on probation
+func (l *TinyLFU[T, K]) unsafeTouch(ele *list.Element) {
+	entry := ele.Value.(*tinyLFUEntry[T, K])
+	switch entry.segment {
+	case tinyLFUWindow:
+		l.window.MoveToBack(ele)
+	case tinyLFUProtected:
+		l.protected.MoveToBack(ele)
+	case tinyLFUProbation:
+		l.probation.Remove(ele)
+		entry.segment = tinyLFUProtected
+		l.hashMap[entry.key] = l.protected.PushBack(entry)
+
+		// Demote the least recently used protected entries back to probation
+		for l.protected.Len() > l.protectedCap {
+			demoted := l.protected.Remove(l.protected.Front()).(*tinyLFUEntry[T, K])
+			demoted.segment = tinyLFUProbation
+			l.hashMap[demoted.key] = l.probation.PushBack(demoted)
+		}
+	}
+}
+
+// Not concurrently safe!
+// Moves entries overflowing the window to the main LRU, if they are accessed more frequently than the main LRU's victim.
+func (l *TinyLFU[T, K]) unsafeApplyPolicy(o *opts) {
+	for l.window.Len() > l.windowCap {
+		candidate := l.window.Remove(l.window.Front()).(*tinyLFUEntry[T, K])
+		candidate.segment = tinyLFUProbation
+		l.hashMap[candidate.key] = l.probation.PushBack(candidate)
+
+		if len(l.hashMap) <= l.threshold {
+			continue
+		}
+
+		victim := l.probation.Front()
+		if victim.Value.(*tinyLFUEntry[T, K]) == candidate {
+			victim = l.protected.Front()
+		}
+		evicted := candidate
+		if victim != nil {
+			victimEntry := victim.Value.(*tinyLFUEntry[T, K])
+			if l.sketch.estimate(l.hash(candidate.key)) > l.sketch.estimate(l.hash(victimEntry.key)) {
+				evicted = victimEntry
+			}
+		}
+
+		l.unsafeRemove(l.hashMap[evicted.key])
+		queueEvicted(o, evicted.key, evicted.value)
+	}
+}
+
+// Not concurrently safe!
+func (l *TinyLFU[T, K]) unsafeRemove(ele *list.Element) {
+	entry := ele.Value.(*tinyLFUEntry[T, K])
+	switch entry.segment {
+	case tinyLFUWindow:
+		l.window.Remove(ele)
+	case tinyLFUProbation:
+		l.probation.Remove(ele)
+	case tinyLFUProtected:
+		l.protected.Remove(ele)
+	}
+	delete(l.hashMap, entry.key)
+}
+
+func (l *TinyLFU[T, K]) hash(key T) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(l.seed, k)
+	case int:
+		return maphash.Bytes(l.seed, binary.LittleEndian.AppendUint64(nil, uint64(k)))
+	case int64:
+		return maphash.Bytes(l.seed, binary.LittleEndian.AppendUint64(nil, uint64(k)))
+	case uint64:
+		return maphash.Bytes(l.seed, binary.LittleEndian.AppendUint64(nil, k))
+	default:
+		return maphash.String(l.seed, fmt.Sprint(k))
+	}
+}
+
+func newCountMinSketch(threshold int) *countMinSketch {
+	// Round up the width to a power of 2, so that indexes can be computed using a mask
+	width := uint64(1) << bits.Len64(uint64(max(threshold, 16)-1))
+
+	s := &countMinSketch{
+		mask:       width - 1,
+		sampleSize: 10 * max(threshold, 16),
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+func (s *countMinSketch) increment(hash uint64) {
+	var added bool
+	for i := range s.rows {
+		idx := s.index(hash, i)
+		if s.rows[i][idx] < 15 {
+			s.rows[i][idx]++
+			added = true
+		}
+	}
+
+	if added {
+		s.additions++
+		if s.additions >= s.sampleSize {
+			s.reset()
+		}
+	}
+}
+
+func (s *countMinSketch) estimate(hash uint64) uint8 {
+	minCount := uint8(15)
+	for i := range s.rows {
+		minCount = min(minCount, s.rows[i][s.index(hash, i)])
+	}
+	return minCount
+}
+
+// Halve every counter, so that the frequency of keys that are no longer accessed decreases over time
+func (s *countMinSketch) reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
+
+// Derive an index for each row from the two halves of the hash
+func (s *countMinSketch) index(hash uint64, row int) uint64 {
+	h1, h2 := hash, (hash>>32)|1
+	return (h1 + uint64(row)*h2) & s.mask
+}
@@ -0,0 +1,125 @@
+package inmem_test
+
+import (
+	"github.com/MysteriousPotato/nitecache/inmem"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestTinyLFU(t *testing.T) {
+	tinyLFU := inmem.NewTinyLFU[int, int](100)
+	for i := 0; i < 100; i++ {
+		if exists := tinyLFU.Put(i, i); exists {
+			t.Fatalf("Expected exists %t, got %t for put operation", false, exists)
+		}
+	}
+	if exists := tinyLFU.Put(1, 10); !exists {
+		t.Fatalf("Expected exists %t, got %t for put operation", true, exists)
+	}
+	if v, ok := tinyLFU.Get(1); !ok || v != 10 {
+		t.Fatalf("Expected %d for key %d, got %d", 10, 1, v)
+	}
+
+	if exists := tinyLFU.Evict(1); !exists {
+		t.Fatalf("Expected exists %t, got %t for evict operation", true, exists)
+	}
+	if exists := tinyLFU.Evict(1); exists {
+		t.Fatalf("Expected exists %t, got %t for evict operation", false, exists)
+	}
+	if l := tinyLFU.Len(); l != 99 {
+		t.Fatalf("Expected %d entries, got %d", 99, l)
+	}
+}
+
+func TestTinyLFUScanResistance(t *testing.T) {
+	tinyLFU := inmem.NewTinyLFU[int, int](100)
+
+	// Make the first 50 keys popular
+	for i := 0; i < 10; i++ {
+		for key := 0; key < 50; key++ {
+			if _, ok := tinyLFU.Get(key); !ok {
+				tinyLFU.Put(key, key, inmem.SkipInc(true))
+			}
+		}
+	}
+
+	// A scan of keys accessed only once must not evict popular keys
+	for key := 1000; key < 2000; key++ {
+		if _, ok := tinyLFU.Get(key); !ok {
+			tinyLFU.Put(key, key, inmem.SkipInc(true))
+		}
+	}
+
+	values := tinyLFU.Values()
+	if len(values) > 100 {
+		t.Fatalf("Expected at most %d entries, got %d", 100, len(values))
+	}
+	// Frequencies are estimated, so a few popular keys may still be evicted
+	var retained int
+	for key := 0; key < 50; key++ {
+		if _, ok := values[key]; ok {
+			retained++
+		}
+	}
+	if retained < 45 {
+		t.Fatalf("Expected at least %d popular keys to be retained, got %d: %v", 45, retained, values)
+	}
+}
+
+func TestTinyLFUConcurrentAccess(t *testing.T) {
+	goroutinesCount := 100
+	iterations := 1000
+
+	tinyLFU := inmem.NewTinyLFU[int, int](128)
+	wg := sync.WaitGroup{}
+
+	wg.Add(goroutinesCount)
+	for i := 0; i < goroutinesCount; i++ {
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < iterations; j++ {
+				tinyLFU.Put(j, j)
+				tinyLFU.Get(j)
+				tinyLFU.Inc(j)
+				tinyLFU.Evict(j)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if values := tinyLFU.Values(); !reflect.DeepEqual(values, map[int]int{}) {
+		t.Fatalf("Expected no values, got %v", values)
+	}
+}
+
+func BenchmarkTinyLFUPut(b *testing.B) {
+	for _, threshold := range []int{10, 100, 1000, 10000, 100000} {
+		b.Run("threshold="+strconv.Itoa(threshold), func(b *testing.B) {
+			tinyLFU := inmem.NewTinyLFU[int, int](threshold)
+			for i := 0; i < b.N; i++ {
+				tinyLFU.Put(i, i)
+			}
+		})
+	}
+}
+
+func BenchmarkTinyLFUGet(b *testing.B) {
+	for _, threshold := range []int{10, 100, 1000, 10000, 100000} {
+		b.Run("threshold="+strconv.Itoa(threshold), func(b *testing.B) {
+			tinyLFU := inmem.NewTinyLFU[int, int](threshold)
+			for i := 0; i < b.N; i++ {
+				tinyLFU.Put(i, i)
+			}
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				tinyLFU.Get(i)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package nitecache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/MysteriousPotato/nitecache/inmem"
+	"github.com/MysteriousPotato/nitecache/servicepb"
+)
+
+// Maximum number of invalidations sent in a single stream message
+const invalidationChunkSize = 100
+
+type (
+	// HotCacheInvalidation configures how the owner of a key keeps the hot cache of other peers up to date.
+	//
+	// Refer to [TableBuilder.WithHotCacheInvalidation] for enabling invalidation on a [Table].
+	HotCacheInvalidation struct {
+		// If true, entries of other peers' hot cache are replaced by the new value on put.
+		// Peers which don't already hold the key in their hot cache ignore the new value.
+		// Otherwise, they are dropped and fetched again on the next call to [Table.Get].
+		Refresh bool
+		// Notifications are coalesced by key and broadcast once every FlushInterval.
+		//
+		// Defaults to 10ms
+		FlushInterval time.Duration
+	}
+	// invalidator batches the put/evict notifications of a table and broadcasts them to every other member.
+	invalidator struct {
+		name    string
+		cache   *Cache
+		cfg     HotCacheInvalidation
+		mu      sync.Mutex
+		pending map[string]*inmem.Item[[]byte]
+		cancel  func()
+		done    chan struct{}
+	}
+)
+
+func newInvalidator(name string, c *Cache, cfg HotCacheInvalidation) *invalidator {
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = time.Millisecond * 10
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	inv := &invalidator{
+		name:    name,
+		cache:   c,
+		cfg:     cfg,
+		pending: map[string]*inmem.Item[[]byte]{},
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go inv.run(ctx)
+
+	return inv
+}
+
+// Queue a notification for the given key, replacing any pending notification for the same key.
+//
+// A nil item means the key was evicted.
+func (inv *invalidator) notify(key string, item *inmem.Item[[]byte]) {
+	if !inv.cfg.Refresh {
+		item = nil
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	inv.pending[key] = item
+}
+
+func (inv *invalidator) run(ctx context.Context) {
+	defer close(inv.done)
+
+	ticker := time.NewTicker(inv.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// In-flight notifications are not cancelled, so that stop doesn't drop them
+			inv.flush(context.Background())
+		}
+	}
+}
+
+// Stop the flush loop, then broadcast the remaining notifications.
+func (inv *invalidator) stop() {
+	inv.cancel()
+	<-inv.done
+
+	inv.flush(context.Background())
+}
+
+// Broadcast pending notifications to every other member.
+//
+// Notifications are best effort: members that can't be reached keep serving their hot cache until the entries expire.
+func (inv *invalidator) flush(ctx context.Context) {
+	inv.mu.Lock()
+	pending := inv.pending
+	if len(pending) == 0 {
+		inv.mu.Unlock()
+		return
+	}
+	inv.pending = map[string]*inmem.Item[[]byte]{}
+	inv.mu.Unlock()
+
+	invalidations := make([]*servicepb.Invalidation, 0, len(pending))
+	for key, item := range pending {
+		invalidation := &servicepb.Invalidation{Key: key}
+		if item != nil {
+			invalidation.Item = toPBItem(*item)
+		}
+		invalidations = append(invalidations, invalidation)
+	}
+
+	inv.cache.membersMu.Lock()
+	members := inv.cache.members
+	inv.cache.membersMu.Unlock()
+
+	wg := sync.WaitGroup{}
+	for _, m := range members {
+		if m.ID == inv.cache.self.ID {
+			continue
+		}
+
+		peer, err := inv.cache.getClient(m.ID)
+		if err != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(peer *client) {
+			defer wg.Done()
+			_ = inv.send(ctx, invalidations, peer)
+		}(peer)
+	}
+	wg.Wait()
+}
+
+func (inv *invalidator) send(ctx context.Context, invalidations []*servicepb.Invalidation, peer *client) error {
+	ctx, cancel := context.WithTimeout(ctx, inv.cache.timeout)
+	defer cancel()
+
+	stream, err := peer.Invalidate(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(invalidations); i += invalidationChunkSize {
+		if err := stream.Send(&servicepb.InvalidateRequest{
+			Table:         inv.name,
+			Invalidations: invalidations[i:min(i+invalidationChunkSize, len(invalidations))],
+		}); err != nil {
+			return err
+		}
+	}
+
+	_, err = stream.CloseAndRecv()
+	return err
+}
@@ -0,0 +1,122 @@
+package nitecache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MysteriousPotato/nitecache"
+	test "github.com/MysteriousPotato/nitecache/test_utils"
+)
+
+func TestHotCacheInvalidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		refresh bool
+	}{
+		{name: "drop", refresh: false},
+		{name: "refresh", refresh: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members := []nitecache.Member{
+				{
+					ID:   "1",
+					Addr: test.GetUniqueAddr(),
+				}, {
+					ID:   "2",
+					Addr: test.GetUniqueAddr(),
+				}, {
+					ID:   "3",
+					Addr: test.GetUniqueAddr(),
+				},
+			}
+
+			caches := make([]*nitecache.Cache, len(members))
+			tables := make([]*nitecache.Table[string], len(members))
+			for i, m := range members {
+				c, err := nitecache.NewCache(m, members,
+					nitecache.VirtualNodeOpt(1),
+					nitecache.HashFuncOpt(test.SimpleHashFunc),
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				go func() {
+					if err := c.ListenAndServe(); err != nil {
+						t.Error(err)
+						return
+					}
+				}()
+
+				caches[i] = c
+				tables[i] = nitecache.NewTable[string]("test").
+					WithHotCache(nitecache.LRU(100)).
+					WithHotCacheInvalidation(nitecache.HotCacheInvalidation{
+						Refresh:       tt.refresh,
+						FlushInterval: time.Millisecond * 5,
+					}).
+					Build(c)
+			}
+
+			for _, c := range caches {
+				test.WaitForServer(t, c)
+			}
+
+			// Key "2" is owned by member "2", populate the hot cache of member "1"
+			ctx := context.Background()
+			if err := tables[1].Put(ctx, "2", "first", time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			// Let the notification for the first put go through before populating the hot cache
+			time.Sleep(time.Millisecond * 50)
+
+			if _, err := tables[0].Get(ctx, "2"); err != nil {
+				t.Fatal(err)
+			}
+			if v, err := tables[0].GetHot("2"); err != nil || v != "first" {
+				t.Fatalf("expected hot value %q, got: %q, err: %v", "first", v, err)
+			}
+
+			// Updated by member "3", so member "1" only learns about it through invalidation
+			if err := tables[2].Put(ctx, "2", "second", time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			waitFor(t, "hot cache to be invalidated on put", func() bool {
+				v, err := tables[0].GetHot("2")
+				if tt.refresh {
+					return err == nil && v == "second"
+				}
+				return errors.Is(err, nitecache.ErrKeyNotFound)
+			})
+
+			if _, err := tables[0].Get(ctx, "2"); err != nil {
+				t.Fatal(err)
+			}
+			if err := tables[2].Evict(ctx, "2"); err != nil {
+				t.Fatal(err)
+			}
+			waitFor(t, "hot cache to be invalidated on evict", func() bool {
+				_, err := tables[0].GetHot("2")
+				return errors.Is(err, nitecache.ErrKeyNotFound)
+			})
+
+			// Member "1" no longer holds the key, so it must not be cached again following a put
+			if err := tables[1].Put(ctx, "2", "third", time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(time.Millisecond * 50)
+
+			if _, err := tables[0].GetHot("2"); !errors.Is(err, nitecache.ErrKeyNotFound) {
+				t.Fatalf("expected hot cache to ignore the put, got err: %v", err)
+			}
+
+			for _, c := range caches {
+				test.TearDown(t, c)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package nitecache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// Trailer used by members to piggyback their load on responses
+	loadMetadataKey = "nitecache-load"
+	// Loads reported by peers are ignored once older than this
+	loadTTL = time.Second
+)
+
+// Methods that don't serve table operations, and therefore don't count toward the load of the current member
+var unloadedMethods = map[string]bool{
+	"/servicepb.Service/HealthCheck": true,
+	"/servicepb.Service/Ping":        true,
+	"/servicepb.Service/PingReq":     true,
+	"/servicepb.Service/Rebalance":   true,
+	"/servicepb.Service/Invalidate":  true,
+}
+
+type (
+	// loadTracker keeps track of the number of in-flight requests of every member.
+	loadTracker struct {
+		selfID   string
+		epsilon  float64
+		inFlight atomic.Int64
+		mu       sync.Mutex
+		peers    map[string]*peerLoad
+	}
+	peerLoad struct {
+		// Last load reported by the peer
+		reported   int64
+		reportedAt time.Time
+		// Requests sent to the peer that are still awaiting a response
+		pending int64
+	}
+)
+
+// BoundedLoadsOpt enables consistent hashing with bounded loads.
+//
+// Every member gets a capacity of (1+epsilon) times the average number of in-flight requests.
+// Gets skip members over capacity in favor of the next member on the hashring, which fills the value using the table's getter.
+// Tables without a getter only skip to the next replica (see [ReplicationFactorOpt]), since other members can't fill the value.
+// Values filled by members that don't own them are dropped once written or evicted on their owner, using hot cache invalidation
+// (see [TableBuilder.WithHotCacheInvalidation]), which is enabled for every table with a getter.
+// They may still be served for up to [HotCacheInvalidation.FlushInterval] after a write.
+//
+// Members piggyback their load on every response, including gossip pings (see [GossipOpt]).
+// Lower epsilons balance loads more evenly, at the cost of fetching values on members that don't own them more often.
+//
+// Refer to [hashring.BoundedLoads] for more details.
+func BoundedLoadsOpt(epsilon float64) func(c *Cache) {
+	return func(c *Cache) {
+		c.loads = &loadTracker{
+			selfID:  c.self.ID,
+			epsilon: epsilon,
+			peers:   map[string]*peerLoad{},
+		}
+	}
+}
+
+// Returns the current load of the given member
+func (l *loadTracker) load(member string) int64 {
+	if member == l.selfID {
+		return l.inFlight.Load()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	p, ok := l.peers[member]
+	if !ok {
+		return 0
+	}
+	if time.Since(p.reportedAt) > loadTTL {
+		return p.pending
+	}
+	return max(p.reported, p.pending)
+}
+
+// Track a request served by the current member. Returns a function to call once it is done.
+func (l *loadTracker) track() func() {
+	l.inFlight.Add(1)
+	return func() {
+		l.inFlight.Add(-1)
+	}
+}
+
+// Track a request sent to the given peer. Returns a function to call with the load reported in the response, if any.
+func (l *loadTracker) trackPeer(peerID string) func(reported []string) {
+	l.mu.Lock()
+	p, ok := l.peers[peerID]
+	if !ok {
+		p = &peerLoad{}
+		l.peers[peerID] = p
+	}
+	p.pending++
+	l.mu.Unlock()
+
+	return func(reported []string) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		p.pending--
+		if len(reported) == 0 {
+			return
+		}
+		if load, err := strconv.ParseInt(reported[0], 10, 64); err == nil {
+			p.reported, p.reportedAt = load, time.Now()
+		}
+	}
+}
+
+// Record the load reported by peers. Must run after timeoutInterceptor, so that the trailer is not read after a timeout.
+func loadUnaryClientInterceptor(peerID string, l *loadTracker) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		var trailer metadata.MD
+		done := l.trackPeer(peerID)
+
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Trailer(&trailer))...)
+		done(trailer.Get(loadMetadataKey))
+		return err
+	}
+}
+
+// Count in-flight requests and piggyback the load of the current member on responses.
+//
+// Health checks and gossip RPCs are not counted, but still carry the load.
+func loadUnaryServerInterceptor(l *loadTracker) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		defer func() {
+			_ = grpc.SetTrailer(ctx, metadata.Pairs(loadMetadataKey, strconv.FormatInt(l.inFlight.Load(), 10)))
+		}()
+		if !unloadedMethods[info.FullMethod] {
+			defer l.track()()
+		}
+		return handler(ctx, req)
+	}
+}
@@ -2,28 +2,110 @@ package nitecache
 
 import (
 	"maps"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
+// Upper bounds of the latency histogram buckets
+var latencyBuckets = []time.Duration{
+	time.Microsecond * 100,
+	time.Microsecond * 250,
+	time.Microsecond * 500,
+	time.Millisecond,
+	time.Microsecond * 2500,
+	time.Millisecond * 5,
+	time.Millisecond * 10,
+	time.Millisecond * 25,
+	time.Millisecond * 50,
+	time.Millisecond * 100,
+	time.Millisecond * 250,
+	time.Millisecond * 500,
+	time.Second,
+	time.Millisecond * 2500,
+	time.Second * 5,
+}
+
 type (
 	// Metrics are limited to the scope of the owner nodes.
 	//
 	// For example, if node-1 queries node-2, metrics will be registered on node-2 only.
+	// RemoteGetLatency and Peers are the exception, since they are registered by the node sending the RPCs.
 	Metrics struct {
 		Miss  int64
 		Get   int64
 		Put   int64
 		Evict int64
 		Call  map[string]int64
+		// Latency of gets served by the local store, including the getter on cache misses
+		LocalGetLatency Histogram
+		// Latency of gets forwarded to other members
+		RemoteGetLatency Histogram
+		// Latency of the getter set through [TableBuilder.WithGetter]
+		GetterLatency Histogram
+		// Latency of the procedures registered through [TableBuilder.WithProcedure], indexed by procedure name
+		CallLatency map[string]Histogram
+		// RPC failures, indexed by peer ID.
+		//
+		// Only available through [Cache.GetMetrics], since RPCs are not specific to a [Table].
+		Peers map[string]PeerMetrics
+	}
+	// TableMetrics are the [Metrics] of a [Table], along with the number of items it currently holds on the current node.
+	TableMetrics struct {
+		Metrics
+		// Number of items stored on the current node, including expired items that were not evicted yet.
+		Items int
+		// Number of items stored in the hot cache, if enabled.
+		HotItems int
+		// Total cost of the items stored on the current node, if the storage is constrained by a byte budget (see [LRUBytes] and [LFUBytes]).
+		Bytes int
+		// Total cost of the items stored in the hot cache, if its storage is constrained by a byte budget.
+		HotBytes int
+		// Number of expired items removed from the current node by the expiration sweep (see [TableBuilder.WithExpirationSweep]).
+		Expired int64
+	}
+	// Histogram counts observed latencies in buckets.
+	Histogram struct {
+		// Upper bounds of the buckets, in ascending order
+		Bounds []time.Duration
+		// Number of observations for each bucket (i.e. Counts[i] is the number of observations <= Bounds[i] and > Bounds[i-1]).
+		//
+		// The last count holds observations greater than every bound.
+		Counts []int64
+		// Total number of observations
+		Count int64
+		// Sum of every observation
+		Sum time.Duration
+	}
+	// PeerMetrics count the RPCs sent to a peer that failed.
+	PeerMetrics struct {
+		// RPCs that failed for any reason other than a timeout
+		Errors int64
+		// RPCs that timed out
+		Timeouts int64
 	}
 	metrics struct {
-		Miss  atomic.Int64
-		Get   atomic.Int64
-		Put   atomic.Int64
-		Evict atomic.Int64
-		Call  map[string]int64
-		mu    *sync.RWMutex
+		Miss             atomic.Int64
+		Get              atomic.Int64
+		Put              atomic.Int64
+		Evict            atomic.Int64
+		Call             map[string]int64
+		LocalGetLatency  *histogram
+		RemoteGetLatency *histogram
+		GetterLatency    *histogram
+		CallLatency      map[string]*histogram
+		Peers            map[string]*peerMetrics
+		mu               *sync.RWMutex
+	}
+	histogram struct {
+		counts []atomic.Int64
+		count  atomic.Int64
+		sum    atomic.Int64
+	}
+	peerMetrics struct {
+		errors   atomic.Int64
+		timeouts atomic.Int64
 	}
 )
 
@@ -31,19 +113,67 @@ func (m *metrics) getCopy() Metrics {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	callLatency := make(map[string]Histogram, len(m.CallLatency))
+	for procedure, h := range m.CallLatency {
+		callLatency[procedure] = h.getCopy()
+	}
+
+	peers := make(map[string]PeerMetrics, len(m.Peers))
+	for id, p := range m.Peers {
+		peers[id] = PeerMetrics{
+			Errors:   p.errors.Load(),
+			Timeouts: p.timeouts.Load(),
+		}
+	}
+
 	return Metrics{
-		Miss:  m.Miss.Load(),
-		Get:   m.Get.Load(),
-		Put:   m.Put.Load(),
-		Evict: m.Evict.Load(),
-		Call:  maps.Clone(m.Call),
+		Miss:             m.Miss.Load(),
+		Get:              m.Get.Load(),
+		Put:              m.Put.Load(),
+		Evict:            m.Evict.Load(),
+		Call:             maps.Clone(m.Call),
+		LocalGetLatency:  m.LocalGetLatency.getCopy(),
+		RemoteGetLatency: m.RemoteGetLatency.getCopy(),
+		GetterLatency:    m.GetterLatency.getCopy(),
+		CallLatency:      callLatency,
+		Peers:            peers,
+	}
+}
+
+func newHistogram() *histogram {
+	return &histogram{counts: make([]atomic.Int64, len(latencyBuckets)+1)}
+}
+
+func (h *histogram) observe(d time.Duration) {
+	i, _ := slices.BinarySearch(latencyBuckets, d)
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+}
+
+func (h *histogram) getCopy() Histogram {
+	counts := make([]int64, len(h.counts))
+	for i := range h.counts {
+		counts[i] = h.counts[i].Load()
+	}
+
+	return Histogram{
+		Bounds: slices.Clone(latencyBuckets),
+		Counts: counts,
+		Count:  h.count.Load(),
+		Sum:    time.Duration(h.sum.Load()),
 	}
 }
 
 func newMetrics() *metrics {
 	return &metrics{
-		Call: make(map[string]int64),
-		mu:   &sync.RWMutex{},
+		Call:             make(map[string]int64),
+		LocalGetLatency:  newHistogram(),
+		RemoteGetLatency: newHistogram(),
+		GetterLatency:    newHistogram(),
+		CallLatency:      make(map[string]*histogram),
+		Peers:            make(map[string]*peerMetrics),
+		mu:               &sync.RWMutex{},
 	}
 }
 
@@ -83,3 +213,65 @@ func incCall(procedure string, m *metrics) {
 
 	m.Call[procedure]++
 }
+
+func observeLocalGet(d time.Duration, ms ...*metrics) {
+	for _, m := range ms {
+		m.LocalGetLatency.observe(d)
+	}
+}
+
+func observeRemoteGet(d time.Duration, ms ...*metrics) {
+	for _, m := range ms {
+		m.RemoteGetLatency.observe(d)
+	}
+}
+
+func observeGetter(d time.Duration, ms ...*metrics) {
+	for _, m := range ms {
+		m.GetterLatency.observe(d)
+	}
+}
+
+func observeCalls(procedure string, d time.Duration, ms ...*metrics) {
+	for _, m := range ms {
+		observeCall(procedure, d, m)
+	}
+}
+
+func observeCall(procedure string, d time.Duration, m *metrics) {
+	m.mu.RLock()
+	h, ok := m.CallLatency[procedure]
+	m.mu.RUnlock()
+
+	if !ok {
+		m.mu.Lock()
+		if h, ok = m.CallLatency[procedure]; !ok {
+			h = newHistogram()
+			m.CallLatency[procedure] = h
+		}
+		m.mu.Unlock()
+	}
+
+	h.observe(d)
+}
+
+func incPeerErr(peerID string, timeout bool, m *metrics) {
+	m.mu.RLock()
+	p, ok := m.Peers[peerID]
+	m.mu.RUnlock()
+
+	if !ok {
+		m.mu.Lock()
+		if p, ok = m.Peers[peerID]; !ok {
+			p = &peerMetrics{}
+			m.Peers[peerID] = p
+		}
+		m.mu.Unlock()
+	}
+
+	if timeout {
+		p.timeouts.Add(1)
+		return
+	}
+	p.errors.Add(1)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/MysteriousPotato/nitecache"
+	"net"
 	"reflect"
 	"testing"
 	"time"
@@ -106,21 +107,41 @@ func TestMetrics(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if !reflect.DeepEqual(expectedGlobal, gotGlobal) {
+	if !reflect.DeepEqual(expectedGlobal, counters(gotGlobal)) {
 		t.Fatalf("expected global metrics: %+v\ngot:%+v", expectedGlobal, gotGlobal)
 	}
 
-	if !reflect.DeepEqual(expectedTable, gotTable1) {
+	if !reflect.DeepEqual(expectedTable, counters(gotTable1)) {
 		t.Fatalf("expected table metrics: %+v\ngot:%+v", expectedTable, gotTable1)
 	}
 
-	if !reflect.DeepEqual(expectedTable, gotTable2) {
+	if !reflect.DeepEqual(expectedTable, counters(gotTable2)) {
 		t.Fatalf("expected table metrics: %+v\ngot:%+v", expectedTable, gotTable2)
 	}
 
-	if err = c.TearDown(); err != nil {
-		t.Fatal(err)
+	latencies := []struct {
+		name     string
+		got      nitecache.Histogram
+		expected int64
+	}{
+		{name: "global local get", got: gotGlobal.LocalGetLatency, expected: 4},
+		{name: "global remote get", got: gotGlobal.RemoteGetLatency, expected: 0},
+		{name: "global getter", got: gotGlobal.GetterLatency, expected: 0},
+		{name: "global call", got: gotGlobal.CallLatency["function"], expected: 4},
+		{name: "table local get", got: gotTable1.LocalGetLatency, expected: 2},
+		{name: "table call", got: gotTable1.CallLatency["function"], expected: 2},
 	}
+	for _, l := range latencies {
+		var count int64
+		for _, c := range l.got.Counts {
+			count += c
+		}
+		if l.got.Count != l.expected || count != l.expected {
+			t.Fatalf("expected %d %s latency observations, got: %+v", l.expected, l.name, l.got)
+		}
+	}
+
+	test.TearDown(t, c)
 
 	if _, err := tables[0].GetMetrics(); !errors.Is(err, nitecache.ErrCacheDestroyed) {
 		t.Fatalf("expected err: %v\ngot:%v", nitecache.ErrCacheDestroyed, err)
@@ -129,3 +150,108 @@ func TestMetrics(t *testing.T) {
 		t.Fatalf("expected err: %v\ngot:%v", nitecache.ErrCacheDestroyed, err)
 	}
 }
+
+func TestMetrics_Peers(t *testing.T) {
+	// Accept connections without ever responding, so that RPCs time out
+	listener, err := net.Listen("tcp", test.GetUniqueAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	self := nitecache.Member{ID: "1", Addr: test.GetUniqueAddr()}
+	c, err := nitecache.NewCache(self, []nitecache.Member{
+		self,
+		{ID: "2", Addr: listener.Addr().String()},
+		{ID: "3", Addr: test.GetUniqueAddr()},
+	},
+		nitecache.VirtualNodeOpt(1),
+		nitecache.HashFuncOpt(test.SimpleHashFunc),
+		nitecache.TimeoutOpt(time.Millisecond*100),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := nitecache.NewTable[int]("test").Build(c)
+
+	ctx := context.Background()
+	if _, err := table.Get(ctx, "2"); err == nil {
+		t.Fatal("expected get to time out")
+	}
+	if _, err := table.Get(ctx, "3"); err == nil {
+		t.Fatal("expected get to fail")
+	}
+
+	got, err := c.GetMetrics()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]nitecache.PeerMetrics{
+		"2": {Timeouts: 1},
+		"3": {Errors: 1},
+	}
+	if !reflect.DeepEqual(expected, got.Peers) {
+		t.Fatalf("expected peer metrics: %+v\ngot:%+v", expected, got.Peers)
+	}
+	if got.RemoteGetLatency.Count != 2 {
+		t.Fatalf("expected 2 remote get latency observations, got: %+v", got.RemoteGetLatency)
+	}
+
+	test.TearDown(t, c)
+}
+
+func TestMetrics_Expired(t *testing.T) {
+	ctx := context.Background()
+	c, err := nitecache.NewCache(nitecache.Member{ID: "1", Addr: test.GetUniqueAddr()}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDown(t, c)
+
+	table := nitecache.NewTable[int]("test").
+		WithExpirationSweep(time.Millisecond).
+		Build(c)
+
+	if err := table.Put(ctx, "1", 1, time.Millisecond*5); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Put(ctx, "2", 2, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		metrics, err := c.GetTableMetrics()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m := metrics["test"]; m.Expired == 1 && m.Items == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 1 expired item and 1 remaining item, got: %+v", metrics["test"])
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Strip latencies and peer metrics, which depend on timing
+func counters(m nitecache.Metrics) nitecache.Metrics {
+	return nitecache.Metrics{
+		Miss:  m.Miss,
+		Get:   m.Get,
+		Put:   m.Put,
+		Evict: m.Evict,
+		Call:  m.Call,
+	}
+}
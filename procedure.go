@@ -0,0 +1,106 @@
+package nitecache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type (
+	// TypedProcedure defines the type used for registering RPCs through [WithTypedProcedure].
+	//
+	// Unlike [Procedure], arguments are decoded before calling the procedure, and the procedure returns a result distinct from the new value.
+	TypedProcedure[T, A, R any] func(ctx context.Context, v T, args A) (T, R, time.Duration, error)
+	// procedure is the common form of [Procedure] and [TypedProcedure], returning the encoded result, if any.
+	procedure[T any] func(ctx context.Context, v T, args []byte) (T, []byte, time.Duration, error)
+	// procedureCodecs holds the codecs of a typed procedure, used by [CallTyped] to encode its arguments and decode its result.
+	procedureCodecs struct {
+		args   any
+		result any
+	}
+)
+
+// WithTypedProcedure registers an RPC that can be called using [CallTyped].
+//
+// Arguments and results are encoded using the given codecs. If nil, codecs default to the same codecs as tables (see [Codec]).
+// Typed procedures can also be called using [Table.Call], in which case the arguments must be encoded manually and the result is discarded.
+//
+// Ex.:
+//
+//	nitecache.WithTypedProcedure(nitecache.NewTable[int]("quotas"), "consume",
+//		func(ctx context.Context, used int, n int) (int, int, time.Duration, error) {
+//			return used + n, limit - used - n, 0, nil
+//		}, nil, nil)
+func WithTypedProcedure[T, A, R any](tb *TableBuilder[T], name string, function TypedProcedure[T, A, R], argsCodec Codec[A], resultCodec Codec[R]) *TableBuilder[T] {
+	if argsCodec == nil {
+		argsCodec = defaultCodec[A]()
+	}
+	if resultCodec == nil {
+		resultCodec = defaultCodec[R]()
+	}
+
+	tb.procedures[name] = func(ctx context.Context, v T, b []byte) (T, []byte, time.Duration, error) {
+		var args A
+		if err := argsCodec.Decode(b, &args); err != nil {
+			return v, nil, 0, err
+		}
+
+		newValue, result, ttl, err := function(ctx, v, args)
+		if err != nil {
+			return newValue, nil, 0, err
+		}
+
+		b, err = resultCodec.Encode(result)
+		return newValue, b, ttl, err
+	}
+	tb.typedCodecs[name] = procedureCodecs{args: argsCodec, result: resultCodec}
+	return tb
+}
+
+// CallTyped calls an RPC previously registered through [WithTypedProcedure] on the owner node to update the value for the given key,
+// and returns the result of the procedure.
+//
+// The result type comes first, so that it can be set explicitly while the other types are inferred, i.e.:
+//
+//	remaining, err := nitecache.CallTyped[int](ctx, table, key, "consume", 1)
+//
+// The procedure must also be registered on the current node, since its codecs are used to encode the arguments and decode the result.
+// Returns [ErrProcedureType] if the types don't match those of the procedure.
+// If the procedure was applied, but the value could not be copied to every replica, the result is returned along with an error wrapping [ErrReplication].
+//
+// Refer to [Table.Call] for more details.
+func CallTyped[R, T, A any](ctx context.Context, t *Table[T], key, function string, args A) (_ R, err error) {
+	var empty R
+	if t.isZero() {
+		return empty, ErrCacheDestroyed
+	}
+
+	ctx, span := t.startSpan(ctx, "CallTyped")
+	defer func() { endSpan(span, err) }()
+
+	codecs, ok := t.typedCodecs[function]
+	if !ok {
+		return empty, ErrRPCNotFound
+	}
+	argsCodec, argsOk := codecs.args.(Codec[A])
+	resultCodec, resultOk := codecs.result.(Codec[R])
+	if !argsOk || !resultOk {
+		return empty, ErrProcedureType
+	}
+
+	b, err := argsCodec.Encode(args)
+	if err != nil {
+		return empty, err
+	}
+
+	_, b, err = t.call(ctx, key, function, b)
+	if err != nil && !errors.Is(err, ErrReplication) {
+		return empty, err
+	}
+
+	var result R
+	if err := resultCodec.Decode(b, &result); err != nil {
+		return empty, err
+	}
+	return result, err
+}
@@ -0,0 +1,101 @@
+package nitecache_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MysteriousPotato/nitecache"
+	test "github.com/MysteriousPotato/nitecache/test_utils"
+)
+
+type consumeArgs struct {
+	N     int
+	Limit int
+}
+
+func TestCallTyped(t *testing.T) {
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+
+	errQuotaExceeded := errors.New("quota exceeded")
+
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[int], len(members))
+	for i, m := range members {
+		c, err := nitecache.NewCache(m, members,
+			nitecache.VirtualNodeOpt(1),
+			nitecache.HashFuncOpt(test.SimpleHashFunc),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer test.TearDown(t, c)
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		caches[i] = c
+		tables[i] = nitecache.WithTypedProcedure(nitecache.NewTable[int]("quotas"), "consume",
+			func(_ context.Context, used int, args consumeArgs) (int, int, time.Duration, error) {
+				if used+args.N > args.Limit {
+					return used, 0, 0, errQuotaExceeded
+				}
+				return used + args.N, args.Limit - used - args.N, 0, nil
+			}, nil, nil).
+			Build(c)
+	}
+	for _, c := range caches {
+		test.WaitForServer(t, c)
+	}
+
+	// Called both from the owner and from the other member
+	ctx := context.Background()
+	for _, key := range []string{"1", "2"} {
+		for i, table := range tables {
+			remaining, err := nitecache.CallTyped[int](ctx, table, key, "consume", consumeArgs{N: 3, Limit: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := 10 - 3*(i+1); remaining != expected {
+				t.Fatalf("expected %d remaining for key %s, got: %d", expected, key, remaining)
+			}
+		}
+
+		if _, err := nitecache.CallTyped[int](ctx, tables[0], key, "consume", consumeArgs{N: 5, Limit: 10}); err == nil || !strings.Contains(err.Error(), errQuotaExceeded.Error()) {
+			t.Fatalf("expected error %v, got: %v", errQuotaExceeded, err)
+		}
+
+		// The result is distinct from the stored value
+		if used, err := tables[1].Get(ctx, key); err != nil || used != 6 {
+			t.Fatalf("expected value %d for key %s, got: %d, err: %v", 6, key, used, err)
+		}
+	}
+
+	// Typed procedures can still be called with encoded arguments
+	if used, err := tables[0].Call(ctx, "1", "consume", []byte(`{"N":1,"Limit":10}`)); err != nil || used != 7 {
+		t.Fatalf("expected value %d, got: %d, err: %v", 7, used, err)
+	}
+
+	if _, err := nitecache.CallTyped[string](ctx, tables[0], "1", "consume", consumeArgs{N: 1, Limit: 10}); !errors.Is(err, nitecache.ErrProcedureType) {
+		t.Fatalf("expected error %v, got: %v", nitecache.ErrProcedureType, err)
+	}
+	if _, err := nitecache.CallTyped[int](ctx, tables[0], "1", "consume", 1); !errors.Is(err, nitecache.ErrProcedureType) {
+		t.Fatalf("expected error %v, got: %v", nitecache.ErrProcedureType, err)
+	}
+	if _, err := nitecache.CallTyped[int](ctx, tables[0], "1", "unknown", 1); !errors.Is(err, nitecache.ErrRPCNotFound) {
+		t.Fatalf("expected error %v, got: %v", nitecache.ErrRPCNotFound, err)
+	}
+}
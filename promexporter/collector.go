@@ -0,0 +1,206 @@
+// Package promexporter exposes the metrics of a [nitecache.Cache] as a [prometheus.Collector].
+//
+// Ex.:
+//
+//	prometheus.MustRegister(promexporter.NewCollector(c))
+package promexporter
+
+import (
+	"github.com/MysteriousPotato/nitecache"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "nitecache"
+
+// Collector implements [prometheus.Collector] using the metrics of a [nitecache.Cache].
+//
+// Metrics are gathered on every scrape, using [nitecache.Cache.GetTableMetrics] and [nitecache.Cache.GetRingMembers].
+// Once the cache is torn down, no metrics are collected.
+type Collector struct {
+	cache       *nitecache.Cache
+	gets        *prometheus.Desc
+	misses      *prometheus.Desc
+	puts        *prometheus.Desc
+	evictions   *prometheus.Desc
+	expired     *prometheus.Desc
+	calls       *prometheus.Desc
+	items       *prometheus.Desc
+	hotItems    *prometheus.Desc
+	bytes       *prometheus.Desc
+	hotBytes    *prometheus.Desc
+	ringMembers *prometheus.Desc
+	localGet    *prometheus.Desc
+	remoteGet   *prometheus.Desc
+	getter      *prometheus.Desc
+	callLatency *prometheus.Desc
+	peerErrors  *prometheus.Desc
+	peerTimeout *prometheus.Desc
+}
+
+// NewCollector creates a new [Collector] for the given cache.
+func NewCollector(c *nitecache.Cache) *Collector {
+	tableLabels := []string{"table"}
+	return &Collector{
+		cache: c,
+		gets: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "gets_total"),
+			"Number of gets handled by the current node.",
+			tableLabels, nil,
+		),
+		misses: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "misses_total"),
+			"Number of gets handled by the current node for keys that were not found.",
+			tableLabels, nil,
+		),
+		puts: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "puts_total"),
+			"Number of puts handled by the current node.",
+			tableLabels, nil,
+		),
+		evictions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "evictions_total"),
+			"Number of explicit evictions handled by the current node.",
+			tableLabels, nil,
+		),
+		expired: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "expired_total"),
+			"Number of expired items removed from the current node by the expiration sweep.",
+			tableLabels, nil,
+		),
+		calls: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "calls_total"),
+			"Number of procedure calls handled by the current node.",
+			[]string{"table", "procedure"}, nil,
+		),
+		items: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "items"),
+			"Number of items stored on the current node.",
+			tableLabels, nil,
+		),
+		hotItems: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "hot_items"),
+			"Number of items stored in the hot cache of the current node.",
+			tableLabels, nil,
+		),
+		bytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "bytes"),
+			"Total cost of the items stored on the current node, if the storage is constrained by a byte budget.",
+			tableLabels, nil,
+		),
+		hotBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "hot_bytes"),
+			"Total cost of the items stored in the hot cache of the current node, if its storage is constrained by a byte budget.",
+			tableLabels, nil,
+		),
+		ringMembers: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ring", "members"),
+			"Number of members on the hashring.",
+			nil, nil,
+		),
+		localGet: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "local_get_duration_seconds"),
+			"Latency of gets served by the local store, including the getter on cache misses.",
+			tableLabels, nil,
+		),
+		remoteGet: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "remote_get_duration_seconds"),
+			"Latency of gets forwarded to other members.",
+			tableLabels, nil,
+		),
+		getter: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "getter_duration_seconds"),
+			"Latency of the getter.",
+			tableLabels, nil,
+		),
+		callLatency: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "table", "call_duration_seconds"),
+			"Latency of procedures.",
+			[]string{"table", "procedure"}, nil,
+		),
+		peerErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "peer", "rpc_errors_total"),
+			"Number of RPCs sent to a peer that failed for any reason other than a timeout.",
+			[]string{"peer"}, nil,
+		),
+		peerTimeout: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "peer", "rpc_timeouts_total"),
+			"Number of RPCs sent to a peer that timed out.",
+			[]string{"peer"}, nil,
+		),
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.gets
+	ch <- c.misses
+	ch <- c.puts
+	ch <- c.evictions
+	ch <- c.expired
+	ch <- c.calls
+	ch <- c.items
+	ch <- c.hotItems
+	ch <- c.bytes
+	ch <- c.hotBytes
+	ch <- c.ringMembers
+	ch <- c.localGet
+	ch <- c.remoteGet
+	ch <- c.getter
+	ch <- c.callLatency
+	ch <- c.peerErrors
+	ch <- c.peerTimeout
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	tablesMetrics, err := c.cache.GetTableMetrics()
+	if err != nil {
+		return
+	}
+
+	for table, m := range tablesMetrics {
+		ch <- prometheus.MustNewConstMetric(c.gets, prometheus.CounterValue, float64(m.Get), table)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(m.Miss), table)
+		ch <- prometheus.MustNewConstMetric(c.puts, prometheus.CounterValue, float64(m.Put), table)
+		ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(m.Evict), table)
+		ch <- prometheus.MustNewConstMetric(c.expired, prometheus.CounterValue, float64(m.Expired), table)
+		for procedure, count := range m.Call {
+			ch <- prometheus.MustNewConstMetric(c.calls, prometheus.CounterValue, float64(count), table, procedure)
+		}
+		ch <- prometheus.MustNewConstMetric(c.items, prometheus.GaugeValue, float64(m.Items), table)
+		ch <- prometheus.MustNewConstMetric(c.hotItems, prometheus.GaugeValue, float64(m.HotItems), table)
+		ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.GaugeValue, float64(m.Bytes), table)
+		ch <- prometheus.MustNewConstMetric(c.hotBytes, prometheus.GaugeValue, float64(m.HotBytes), table)
+		ch <- newConstHistogram(c.localGet, m.LocalGetLatency, table)
+		ch <- newConstHistogram(c.remoteGet, m.RemoteGetLatency, table)
+		ch <- newConstHistogram(c.getter, m.GetterLatency, table)
+		for procedure, h := range m.CallLatency {
+			ch <- newConstHistogram(c.callLatency, h, table, procedure)
+		}
+	}
+
+	members, err := c.cache.GetRingMembers()
+	if err != nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.ringMembers, prometheus.GaugeValue, float64(len(members)))
+
+	m, err := c.cache.GetMetrics()
+	if err != nil {
+		return
+	}
+	for peer, p := range m.Peers {
+		ch <- prometheus.MustNewConstMetric(c.peerErrors, prometheus.CounterValue, float64(p.Errors), peer)
+		ch <- prometheus.MustNewConstMetric(c.peerTimeout, prometheus.CounterValue, float64(p.Timeouts), peer)
+	}
+}
+
+// Prometheus buckets are cumulative, unlike [nitecache.Histogram]
+func newConstHistogram(desc *prometheus.Desc, h nitecache.Histogram, labels ...string) prometheus.Metric {
+	buckets := make(map[float64]uint64, len(h.Bounds))
+	var cumulative uint64
+	for i, bound := range h.Bounds {
+		cumulative += uint64(h.Counts[i])
+		buckets[bound.Seconds()] = cumulative
+	}
+
+	return prometheus.MustNewConstHistogram(desc, uint64(h.Count), h.Sum.Seconds(), buckets, labels...)
+}
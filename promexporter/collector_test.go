@@ -0,0 +1,108 @@
+package promexporter_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MysteriousPotato/nitecache"
+	"github.com/MysteriousPotato/nitecache/promexporter"
+	test "github.com/MysteriousPotato/nitecache/test_utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	ctx := context.Background()
+	c, err := nitecache.NewCache(nitecache.Member{ID: "1", Addr: test.GetUniqueAddr()}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := nitecache.NewTable[int]("test").
+		WithStorage(nitecache.LRUBytes(1024, nil)).
+		WithProcedure("increment", func(_ context.Context, v int, _ []byte) (int, time.Duration, error) {
+			return v + 1, 0, nil
+		}).
+		Build(c)
+
+	if err := table.Put(ctx, "1", 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Put(ctx, "2", 2, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := table.Get(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := table.Get(ctx, "3"); err == nil {
+		t.Fatal("expected key 3 to be missing")
+	}
+	if _, err := table.Call(ctx, "1", "increment", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Evict(ctx, "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	collector := promexporter.NewCollector(c)
+	expected := `
+# HELP nitecache_ring_members Number of members on the hashring.
+# TYPE nitecache_ring_members gauge
+nitecache_ring_members 1
+# HELP nitecache_table_bytes Total cost of the items stored on the current node, if the storage is constrained by a byte budget.
+# TYPE nitecache_table_bytes gauge
+nitecache_table_bytes{table="test"} 2
+# HELP nitecache_table_calls_total Number of procedure calls handled by the current node.
+# TYPE nitecache_table_calls_total counter
+nitecache_table_calls_total{procedure="increment",table="test"} 1
+# HELP nitecache_table_evictions_total Number of explicit evictions handled by the current node.
+# TYPE nitecache_table_evictions_total counter
+nitecache_table_evictions_total{table="test"} 1
+# HELP nitecache_table_gets_total Number of gets handled by the current node.
+# TYPE nitecache_table_gets_total counter
+nitecache_table_gets_total{table="test"} 2
+# HELP nitecache_table_hot_items Number of items stored in the hot cache of the current node.
+# TYPE nitecache_table_hot_items gauge
+nitecache_table_hot_items{table="test"} 0
+# HELP nitecache_table_items Number of items stored on the current node.
+# TYPE nitecache_table_items gauge
+nitecache_table_items{table="test"} 1
+# HELP nitecache_table_misses_total Number of gets handled by the current node for keys that were not found.
+# TYPE nitecache_table_misses_total counter
+nitecache_table_misses_total{table="test"} 1
+# HELP nitecache_table_puts_total Number of puts handled by the current node.
+# TYPE nitecache_table_puts_total counter
+nitecache_table_puts_total{table="test"} 2
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"nitecache_ring_members",
+		"nitecache_table_bytes",
+		"nitecache_table_calls_total",
+		"nitecache_table_evictions_total",
+		"nitecache_table_gets_total",
+		"nitecache_table_hot_items",
+		"nitecache_table_items",
+		"nitecache_table_misses_total",
+		"nitecache_table_puts_total",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	// Latencies depend on timing, only check that they are exposed
+	for name, expected := range map[string]int{
+		"nitecache_table_local_get_duration_seconds":  1,
+		"nitecache_table_remote_get_duration_seconds": 1,
+		"nitecache_table_getter_duration_seconds":     1,
+		"nitecache_table_call_duration_seconds":       1,
+	} {
+		if n := testutil.CollectAndCount(collector, name); n != expected {
+			t.Fatalf("expected %d %s metrics, got: %d", expected, name, n)
+		}
+	}
+
+	test.TearDown(t, c)
+	if n := testutil.CollectAndCount(collector); n != 0 {
+		t.Fatalf("expected no metrics after tear down, got: %d", n)
+	}
+}
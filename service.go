@@ -2,12 +2,16 @@ package nitecache
 
 import (
 	"context"
+	"errors"
 	"github.com/MysteriousPotato/nitecache/inmem"
+	"io"
 	"net"
 	"time"
 
 	"github.com/MysteriousPotato/nitecache/servicepb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type service struct {
@@ -28,11 +32,24 @@ type (
 	clients map[string]*client
 )
 
-func newClient(addr string, c *Cache) (*client, error) {
+func newClient(p Member, c *Cache) (*client, error) {
+	unaryInterceptors := []grpc.UnaryClientInterceptor{
+		tracingUnaryClientInterceptor(c),
+		peerMetricsUnaryInterceptor(p.ID, c.metrics),
+		timeoutInterceptor(c.timeout),
+	}
+	if c.loads != nil {
+		unaryInterceptors = append(unaryInterceptors, loadUnaryClientInterceptor(p.ID, c.loads))
+	}
+
 	conn, err := grpc.Dial(
-		addr,
+		p.Addr,
 		grpc.WithTransportCredentials(c.transportCredentials),
-		grpc.WithUnaryInterceptor(timeoutInterceptor(c.timeout)),
+		grpc.WithChainUnaryInterceptor(unaryInterceptors...),
+		grpc.WithChainStreamInterceptor(
+			tracingStreamClientInterceptor(),
+			peerMetricsStreamInterceptor(p.ID, c.metrics),
+		),
 	)
 	if err != nil {
 		return nil, err
@@ -47,7 +64,16 @@ func newClient(addr string, c *Cache) (*client, error) {
 }
 
 func newService(addr string, cache *Cache) (server, error) {
-	grpcServer := grpc.NewServer(cache.grpcOpts...)
+	unaryInterceptors := []grpc.UnaryServerInterceptor{tracingUnaryServerInterceptor(cache)}
+	if cache.loads != nil {
+		unaryInterceptors = append(unaryInterceptors, loadUnaryServerInterceptor(cache.loads))
+	}
+
+	grpcServer := grpc.NewServer(append(
+		cache.grpcOpts,
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(tracingStreamServerInterceptor(cache)),
+	)...)
 	servicepb.RegisterServiceServer(grpcServer, &service{cache: cache})
 
 	listener, err := net.Listen("tcp", addr)
@@ -91,6 +117,77 @@ func timeoutInterceptor(timeout time.Duration) func(
 	}
 }
 
+// Count failed RPCs per peer. Must run before timeoutInterceptor, so that timeouts can be detected
+func peerMetricsUnaryInterceptor(peerID string, m *metrics) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err != nil {
+			incPeerErr(peerID, isTimeout(err), m)
+		}
+		return err
+	}
+}
+
+// Count streams that could not be opened per peer
+func peerMetricsStreamInterceptor(peerID string, m *metrics) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			incPeerErr(peerID, isTimeout(err), m)
+		}
+		return stream, err
+	}
+}
+
+func isTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded
+}
+
+// isUnreachable reports whether err was caused by a peer that could not be reached.
+func isUnreachable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+func toPBItem(item inmem.Item[[]byte]) *servicepb.Item {
+	return &servicepb.Item{
+		Expire:  item.Expire.UnixMicro(),
+		Value:   item.Value,
+		Version: item.Version,
+		Absent:  item.Absent,
+	}
+}
+
+func fromPBItem(item *servicepb.Item) inmem.Item[[]byte] {
+	return inmem.Item[[]byte]{
+		Expire:  time.UnixMicro(item.Expire),
+		Value:   item.Value,
+		Version: item.Version,
+		Absent:  item.Absent,
+	}
+}
+
 func (s service) Get(ctx context.Context, r *servicepb.GetRequest) (*servicepb.GetResponse, error) {
 	t, err := s.cache.getTable(r.Table)
 	if err != nil {
@@ -103,24 +200,82 @@ func (s service) Get(ctx context.Context, r *servicepb.GetRequest) (*servicepb.G
 	}
 
 	return &servicepb.GetResponse{
-		Hit: hit,
-		Item: &servicepb.Item{
-			Expire: item.Expire.UnixMicro(),
-			Value:  item.Value,
-		},
+		Hit:  hit,
+		Item: toPBItem(item),
 	}, nil
 }
 
-func (s service) Put(_ context.Context, r *servicepb.PutRequest) (*servicepb.Empty, error) {
+func (s service) Put(ctx context.Context, r *servicepb.PutRequest) (*servicepb.PutResponse, error) {
 	t, err := s.cache.getTable(r.Table)
 	if err != nil {
 		return nil, err
 	}
 
-	return &servicepb.Empty{}, t.putLocally(r.Key, inmem.Item[[]byte]{
-		Expire: time.UnixMicro(r.Item.Expire),
-		Value:  r.Item.Value,
-	})
+	item, err := t.putLocally(ctx, r.Key, fromPBItem(r.Item))
+	if err != nil {
+		return nil, err
+	}
+
+	return &servicepb.PutResponse{Item: toPBItem(item)}, nil
+}
+
+func (s service) CompareAndSwap(ctx context.Context, r *servicepb.CompareAndSwapRequest) (*servicepb.CompareAndSwapResponse, error) {
+	t, err := s.cache.getTable(r.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	item, swapped, err := t.compareAndSwapLocally(ctx, r.Key, r.ExpectedVersion, fromPBItem(r.Item))
+	if err != nil {
+		return nil, err
+	}
+
+	return &servicepb.CompareAndSwapResponse{
+		Swapped: swapped,
+		Item:    toPBItem(item),
+	}, nil
+}
+
+func (s service) GetMany(ctx context.Context, r *servicepb.GetManyRequest) (*servicepb.GetManyResponse, error) {
+	t, err := s.cache.getTable(r.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[string]*servicepb.GetResponse, len(r.Keys))
+	errs := map[string]string{}
+	for _, key := range r.Keys {
+		item, hit, err := t.getLocally(ctx, key)
+		if err != nil {
+			errs[key] = err.Error()
+			continue
+		}
+
+		items[key] = &servicepb.GetResponse{
+			Hit:  hit,
+			Item: toPBItem(item),
+		}
+	}
+
+	return &servicepb.GetManyResponse{Items: items, Errors: errs}, nil
+}
+
+func (s service) PutMany(ctx context.Context, r *servicepb.PutManyRequest) (*servicepb.PutManyResponse, error) {
+	t, err := s.cache.getTable(r.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[string]*servicepb.Item, len(r.Items))
+	for key, pbItem := range r.Items {
+		item, err := t.putLocally(ctx, key, fromPBItem(pbItem))
+		if err != nil {
+			return nil, err
+		}
+		items[key] = toPBItem(item)
+	}
+
+	return &servicepb.PutManyResponse{Items: items}, nil
 }
 
 func (s service) Evict(_ context.Context, r *servicepb.EvictRequest) (*servicepb.Empty, error) {
@@ -138,9 +293,7 @@ func (s service) EvictAll(_ context.Context, r *servicepb.EvictAllRequest) (*ser
 		return nil, err
 	}
 
-	t.evictAllLocally(r.Keys)
-
-	return &servicepb.Empty{}, nil
+	return &servicepb.Empty{}, t.evictAllLocally(r.Keys)
 }
 
 func (s service) Call(ctx context.Context, r *servicepb.CallRequest) (*servicepb.CallResponse, error) {
@@ -149,19 +302,79 @@ func (s service) Call(ctx context.Context, r *servicepb.CallRequest) (*servicepb
 		return nil, err
 	}
 
-	item, err := t.callLocally(ctx, r.Key, r.Procedure, r.Args)
+	item, result, err := t.callLocally(ctx, r.Key, r.Procedure, r.Args)
 	if err != nil {
 		return nil, err
 	}
 
 	return &servicepb.CallResponse{
-		Item: &servicepb.Item{
-			Expire: item.Expire.UnixMicro(),
-			Value:  item.Value,
-		},
+		Item:   toPBItem(item),
+		Result: result,
 	}, nil
 }
 
 func (s service) HealthCheck(_ context.Context, _ *servicepb.Empty) (*servicepb.Empty, error) {
 	return &servicepb.Empty{}, nil
 }
+
+func (s service) Rebalance(stream servicepb.Service_RebalanceServer) error {
+	for {
+		r, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&servicepb.Empty{})
+		}
+		if err != nil {
+			return err
+		}
+
+		t, err := s.cache.getTable(r.Table)
+		if err != nil {
+			return err
+		}
+
+		if err := t.rebalanceLocally(r.Key, fromPBItem(r.Item)); err != nil {
+			return err
+		}
+	}
+}
+
+func (s service) Invalidate(stream servicepb.Service_InvalidateServer) error {
+	for {
+		r, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&servicepb.Empty{})
+		}
+		if err != nil {
+			return err
+		}
+
+		t, err := s.cache.getTable(r.Table)
+		if err != nil {
+			return err
+		}
+
+		for _, invalidation := range r.Invalidations {
+			if invalidation.Item == nil {
+				t.invalidateLocally(invalidation.Key, nil)
+				continue
+			}
+
+			item := fromPBItem(invalidation.Item)
+			t.invalidateLocally(invalidation.Key, &item)
+		}
+	}
+}
+
+func (s service) Ping(_ context.Context, r *servicepb.PingRequest) (*servicepb.PingResponse, error) {
+	if s.cache.gossip == nil {
+		return nil, errGossipDisabled
+	}
+	return s.cache.gossip.handlePing(r.Updates), nil
+}
+
+func (s service) PingReq(ctx context.Context, r *servicepb.PingReqRequest) (*servicepb.PingResponse, error) {
+	if s.cache.gossip == nil {
+		return nil, errGossipDisabled
+	}
+	return s.cache.gossip.handlePingReq(ctx, r)
+}
@@ -19,12 +19,19 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Service_Get_FullMethodName         = "/servicepb.Service/Get"
-	Service_Put_FullMethodName         = "/servicepb.Service/Put"
-	Service_Evict_FullMethodName       = "/servicepb.Service/Evict"
-	Service_EvictAll_FullMethodName    = "/servicepb.Service/EvictAll"
-	Service_Call_FullMethodName        = "/servicepb.Service/Call"
-	Service_HealthCheck_FullMethodName = "/servicepb.Service/HealthCheck"
+	Service_Get_FullMethodName            = "/servicepb.Service/Get"
+	Service_Put_FullMethodName            = "/servicepb.Service/Put"
+	Service_GetMany_FullMethodName        = "/servicepb.Service/GetMany"
+	Service_PutMany_FullMethodName        = "/servicepb.Service/PutMany"
+	Service_CompareAndSwap_FullMethodName = "/servicepb.Service/CompareAndSwap"
+	Service_Evict_FullMethodName          = "/servicepb.Service/Evict"
+	Service_EvictAll_FullMethodName       = "/servicepb.Service/EvictAll"
+	Service_Call_FullMethodName           = "/servicepb.Service/Call"
+	Service_HealthCheck_FullMethodName    = "/servicepb.Service/HealthCheck"
+	Service_Rebalance_FullMethodName      = "/servicepb.Service/Rebalance"
+	Service_Ping_FullMethodName           = "/servicepb.Service/Ping"
+	Service_PingReq_FullMethodName        = "/servicepb.Service/PingReq"
+	Service_Invalidate_FullMethodName     = "/servicepb.Service/Invalidate"
 )
 
 // ServiceClient is the client API for Service service.
@@ -32,11 +39,18 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ServiceClient interface {
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
-	Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*Empty, error)
+	Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error)
+	GetMany(ctx context.Context, in *GetManyRequest, opts ...grpc.CallOption) (*GetManyResponse, error)
+	PutMany(ctx context.Context, in *PutManyRequest, opts ...grpc.CallOption) (*PutManyResponse, error)
+	CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, opts ...grpc.CallOption) (*CompareAndSwapResponse, error)
 	Evict(ctx context.Context, in *EvictRequest, opts ...grpc.CallOption) (*Empty, error)
 	EvictAll(ctx context.Context, in *EvictAllRequest, opts ...grpc.CallOption) (*Empty, error)
 	Call(ctx context.Context, in *CallRequest, opts ...grpc.CallOption) (*CallResponse, error)
 	HealthCheck(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	Rebalance(ctx context.Context, opts ...grpc.CallOption) (Service_RebalanceClient, error)
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	PingReq(ctx context.Context, in *PingReqRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	Invalidate(ctx context.Context, opts ...grpc.CallOption) (Service_InvalidateClient, error)
 }
 
 type serviceClient struct {
@@ -56,8 +70,8 @@ func (c *serviceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.Ca
 	return out, nil
 }
 
-func (c *serviceClient) Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *serviceClient) Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error) {
+	out := new(PutResponse)
 	err := c.cc.Invoke(ctx, Service_Put_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -65,6 +79,33 @@ func (c *serviceClient) Put(ctx context.Context, in *PutRequest, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *serviceClient) GetMany(ctx context.Context, in *GetManyRequest, opts ...grpc.CallOption) (*GetManyResponse, error) {
+	out := new(GetManyResponse)
+	err := c.cc.Invoke(ctx, Service_GetMany_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) PutMany(ctx context.Context, in *PutManyRequest, opts ...grpc.CallOption) (*PutManyResponse, error) {
+	out := new(PutManyResponse)
+	err := c.cc.Invoke(ctx, Service_PutMany_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, opts ...grpc.CallOption) (*CompareAndSwapResponse, error) {
+	out := new(CompareAndSwapResponse)
+	err := c.cc.Invoke(ctx, Service_CompareAndSwap_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *serviceClient) Evict(ctx context.Context, in *EvictRequest, opts ...grpc.CallOption) (*Empty, error) {
 	out := new(Empty)
 	err := c.cc.Invoke(ctx, Service_Evict_FullMethodName, in, out, opts...)
@@ -101,16 +142,109 @@ func (c *serviceClient) HealthCheck(ctx context.Context, in *Empty, opts ...grpc
 	return out, nil
 }
 
+func (c *serviceClient) Rebalance(ctx context.Context, opts ...grpc.CallOption) (Service_RebalanceClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Service_ServiceDesc.Streams[0], Service_Rebalance_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &serviceRebalanceClient{stream}
+	return x, nil
+}
+
+type Service_RebalanceClient interface {
+	Send(*RebalanceRequest) error
+	CloseAndRecv() (*Empty, error)
+	grpc.ClientStream
+}
+
+type serviceRebalanceClient struct {
+	grpc.ClientStream
+}
+
+func (x *serviceRebalanceClient) Send(m *RebalanceRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *serviceRebalanceClient) CloseAndRecv() (*Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *serviceClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
+	out := new(PingResponse)
+	err := c.cc.Invoke(ctx, Service_Ping_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) PingReq(ctx context.Context, in *PingReqRequest, opts ...grpc.CallOption) (*PingResponse, error) {
+	out := new(PingResponse)
+	err := c.cc.Invoke(ctx, Service_PingReq_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) Invalidate(ctx context.Context, opts ...grpc.CallOption) (Service_InvalidateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Service_ServiceDesc.Streams[1], Service_Invalidate_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &serviceInvalidateClient{stream}
+	return x, nil
+}
+
+type Service_InvalidateClient interface {
+	Send(*InvalidateRequest) error
+	CloseAndRecv() (*Empty, error)
+	grpc.ClientStream
+}
+
+type serviceInvalidateClient struct {
+	grpc.ClientStream
+}
+
+func (x *serviceInvalidateClient) Send(m *InvalidateRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *serviceInvalidateClient) CloseAndRecv() (*Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ServiceServer is the server API for Service service.
 // All implementations must embed UnimplementedServiceServer
 // for forward compatibility
 type ServiceServer interface {
 	Get(context.Context, *GetRequest) (*GetResponse, error)
-	Put(context.Context, *PutRequest) (*Empty, error)
+	Put(context.Context, *PutRequest) (*PutResponse, error)
+	GetMany(context.Context, *GetManyRequest) (*GetManyResponse, error)
+	PutMany(context.Context, *PutManyRequest) (*PutManyResponse, error)
+	CompareAndSwap(context.Context, *CompareAndSwapRequest) (*CompareAndSwapResponse, error)
 	Evict(context.Context, *EvictRequest) (*Empty, error)
 	EvictAll(context.Context, *EvictAllRequest) (*Empty, error)
 	Call(context.Context, *CallRequest) (*CallResponse, error)
 	HealthCheck(context.Context, *Empty) (*Empty, error)
+	Rebalance(Service_RebalanceServer) error
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	PingReq(context.Context, *PingReqRequest) (*PingResponse, error)
+	Invalidate(Service_InvalidateServer) error
 	mustEmbedUnimplementedServiceServer()
 }
 
@@ -121,9 +255,18 @@ type UnimplementedServiceServer struct {
 func (UnimplementedServiceServer) Get(context.Context, *GetRequest) (*GetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
 }
-func (UnimplementedServiceServer) Put(context.Context, *PutRequest) (*Empty, error) {
+func (UnimplementedServiceServer) Put(context.Context, *PutRequest) (*PutResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Put not implemented")
 }
+func (UnimplementedServiceServer) GetMany(context.Context, *GetManyRequest) (*GetManyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMany not implemented")
+}
+func (UnimplementedServiceServer) PutMany(context.Context, *PutManyRequest) (*PutManyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutMany not implemented")
+}
+func (UnimplementedServiceServer) CompareAndSwap(context.Context, *CompareAndSwapRequest) (*CompareAndSwapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareAndSwap not implemented")
+}
 func (UnimplementedServiceServer) Evict(context.Context, *EvictRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Evict not implemented")
 }
@@ -136,6 +279,18 @@ func (UnimplementedServiceServer) Call(context.Context, *CallRequest) (*CallResp
 func (UnimplementedServiceServer) HealthCheck(context.Context, *Empty) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
 }
+func (UnimplementedServiceServer) Rebalance(Service_RebalanceServer) error {
+	return status.Errorf(codes.Unimplemented, "method Rebalance not implemented")
+}
+func (UnimplementedServiceServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (UnimplementedServiceServer) PingReq(context.Context, *PingReqRequest) (*PingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PingReq not implemented")
+}
+func (UnimplementedServiceServer) Invalidate(Service_InvalidateServer) error {
+	return status.Errorf(codes.Unimplemented, "method Invalidate not implemented")
+}
 func (UnimplementedServiceServer) mustEmbedUnimplementedServiceServer() {}
 
 // UnsafeServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -185,6 +340,60 @@ func _Service_Put_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Service_GetMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetManyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).GetMany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_GetMany_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).GetMany(ctx, req.(*GetManyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_PutMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutManyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).PutMany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_PutMany_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).PutMany(ctx, req.(*PutManyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_CompareAndSwap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareAndSwapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).CompareAndSwap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_CompareAndSwap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).CompareAndSwap(ctx, req.(*CompareAndSwapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Service_Evict_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(EvictRequest)
 	if err := dec(in); err != nil {
@@ -257,6 +466,94 @@ func _Service_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Service_Rebalance_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ServiceServer).Rebalance(&serviceRebalanceServer{stream})
+}
+
+type Service_RebalanceServer interface {
+	SendAndClose(*Empty) error
+	Recv() (*RebalanceRequest, error)
+	grpc.ServerStream
+}
+
+type serviceRebalanceServer struct {
+	grpc.ServerStream
+}
+
+func (x *serviceRebalanceServer) SendAndClose(m *Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *serviceRebalanceServer) Recv() (*RebalanceRequest, error) {
+	m := new(RebalanceRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Service_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_Ping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_PingReq_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingReqRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).PingReq(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_PingReq_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).PingReq(ctx, req.(*PingReqRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_Invalidate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ServiceServer).Invalidate(&serviceInvalidateServer{stream})
+}
+
+type Service_InvalidateServer interface {
+	SendAndClose(*Empty) error
+	Recv() (*InvalidateRequest, error)
+	grpc.ServerStream
+}
+
+type serviceInvalidateServer struct {
+	grpc.ServerStream
+}
+
+func (x *serviceInvalidateServer) SendAndClose(m *Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *serviceInvalidateServer) Recv() (*InvalidateRequest, error) {
+	m := new(InvalidateRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Service_ServiceDesc is the grpc.ServiceDesc for Service service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -272,6 +569,18 @@ var Service_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Put",
 			Handler:    _Service_Put_Handler,
 		},
+		{
+			MethodName: "GetMany",
+			Handler:    _Service_GetMany_Handler,
+		},
+		{
+			MethodName: "PutMany",
+			Handler:    _Service_PutMany_Handler,
+		},
+		{
+			MethodName: "CompareAndSwap",
+			Handler:    _Service_CompareAndSwap_Handler,
+		},
 		{
 			MethodName: "Evict",
 			Handler:    _Service_Evict_Handler,
@@ -288,7 +597,26 @@ var Service_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "HealthCheck",
 			Handler:    _Service_HealthCheck_Handler,
 		},
+		{
+			MethodName: "Ping",
+			Handler:    _Service_Ping_Handler,
+		},
+		{
+			MethodName: "PingReq",
+			Handler:    _Service_PingReq_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Rebalance",
+			Handler:       _Service_Rebalance_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Invalidate",
+			Handler:       _Service_Invalidate_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "servicepb/service.proto",
 }
@@ -0,0 +1,298 @@
+package nitecache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/MysteriousPotato/nitecache/inmem"
+)
+
+const snapshotVersion = 1
+
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+var (
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+	snapshotMagic = []byte("NTCS")
+)
+
+// SnapshotOpt sets a file used to persist the cache between restarts.
+//
+// The file is loaded, if it exists, when calling [NewCache] and written when calling [Cache.TearDown].
+// Refer to [Cache.Snapshot] and [Cache.Restore] for more details.
+func SnapshotOpt(path string) func(c *Cache) {
+	return func(c *Cache) {
+		c.snapshotPath = path
+	}
+}
+
+// Snapshot writes the content of every [Table] of the current node to w.
+//
+// Only the encoded values are written, along with their expiration and version. Expired items and cached absences are skipped.
+//
+// The format is versioned, so that snapshots taken by older versions of nitecache can still be restored.
+func (c *Cache) Snapshot(w io.Writer) error {
+	if c.isZero() {
+		return ErrCacheDestroyed
+	}
+
+	c.tablesMu.Lock()
+	tables := make(map[string]map[string]inmem.Item[[]byte], len(c.tables)+len(c.restored))
+	for name, items := range c.restored {
+		tables[name] = items
+	}
+	var errs []error
+	for name, t := range c.tables {
+		values, err := t.values()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		tables[name] = values
+	}
+	c.tablesMu.Unlock()
+
+	if errs != nil {
+		return fmt.Errorf("unable to take snapshot: %w", errors.Join(errs...))
+	}
+
+	return writeSnapshot(w, tables)
+}
+
+func writeSnapshot(w io.Writer, tables map[string]map[string]inmem.Item[[]byte]) error {
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	bw := bufio.NewWriter(w)
+	buf := append(slices.Clone(snapshotMagic), snapshotVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(names)))
+	if _, err := bw.Write(buf); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, name := range names {
+		items := make(map[string]inmem.Item[[]byte], len(tables[name]))
+		for key, item := range tables[name] {
+			if !item.Absent && (item.Expire.IsZero() || !item.Expire.Before(now)) {
+				items[key] = item
+			}
+		}
+
+		buf = appendBytes(buf[:0], []byte(name))
+		buf = binary.AppendUvarint(buf, uint64(len(items)))
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+
+		for key, item := range items {
+			buf = appendBytes(buf[:0], []byte(key))
+			buf = appendBytes(buf, item.Value)
+			buf = binary.AppendVarint(buf, encodeExpire(item.Expire))
+			buf = binary.AppendUvarint(buf, item.Version)
+			if _, err := bw.Write(buf); err != nil {
+				return err
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Restore loads a snapshot written by [Cache.Snapshot], replacing existing values for the same keys.
+//
+// Values of tables that were not built yet are kept until the table is built using [TableBuilder.Build].
+// Items that expired since the snapshot was taken are skipped.
+//
+// Keys that are no longer owned by the current node are sent to their owners on the next call to [Cache.SetPeers].
+func (c *Cache) Restore(r io.Reader) error {
+	if c.isZero() {
+		return ErrCacheDestroyed
+	}
+
+	tables, err := readSnapshot(bufio.NewReader(r))
+	if err != nil {
+		return err
+	}
+
+	c.tablesMu.Lock()
+	defer c.tablesMu.Unlock()
+
+	var errs []error
+	for name, items := range tables {
+		if t, ok := c.tables[name]; ok {
+			if err := t.restore(items); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		c.restored[name] = items
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Cache) snapshotToFile(path string) error {
+	return writeFileAtomic(path, c.Snapshot)
+}
+
+func (c *Cache) restoreFromFile(path string) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return c.Restore(f)
+}
+
+func readSnapshot(r byteReader) (map[string]map[string]inmem.Item[[]byte], error) {
+	header := make([]byte, len(snapshotMagic)+1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+	if !bytes.Equal(header[:len(snapshotMagic)], snapshotMagic) {
+		return nil, fmt.Errorf("%w: unknown format", ErrInvalidSnapshot)
+	}
+	if version := header[len(snapshotMagic)]; version != snapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, version)
+	}
+
+	tables, err := readSnapshotTables(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+	return tables, nil
+}
+
+func readSnapshotTables(r byteReader) (map[string]map[string]inmem.Item[[]byte], error) {
+	tablesLen, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tables := map[string]map[string]inmem.Item[[]byte]{}
+	for i := uint64(0); i < tablesLen; i++ {
+		name, err := readBytes(r)
+		if err != nil {
+			return nil, err
+		}
+
+		itemsLen, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+
+		items := map[string]inmem.Item[[]byte]{}
+		for j := uint64(0); j < itemsLen; j++ {
+			key, err := readBytes(r)
+			if err != nil {
+				return nil, err
+			}
+			value, err := readBytes(r)
+			if err != nil {
+				return nil, err
+			}
+			expire, err := binary.ReadVarint(r)
+			if err != nil {
+				return nil, err
+			}
+			version, err := binary.ReadUvarint(r)
+			if err != nil {
+				return nil, err
+			}
+
+			item := inmem.Item[[]byte]{
+				Expire:  decodeExpire(expire),
+				Value:   value,
+				Version: version,
+			}
+			if !item.Expire.IsZero() && item.Expire.Before(now) {
+				continue
+			}
+			items[string(key)] = item
+		}
+		tables[string(name)] = items
+	}
+
+	return tables, nil
+}
+
+func appendBytes(buf []byte, b []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+func readBytes(r byteReader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Avoid allocating huge buffers upfront when reading corrupted lengths
+	b, err := io.ReadAll(io.LimitReader(r, int64(n)))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(b)) != n {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return b, nil
+}
+
+// Write to a temporary file first, so that a failure doesn't corrupt the previous content of the file
+func writeFileAtomic(path string, write func(w io.Writer) error) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Items without expiration are encoded as 0
+func encodeExpire(expire time.Time) int64 {
+	if expire.IsZero() {
+		return 0
+	}
+	return expire.UnixMicro()
+}
+
+func decodeExpire(expire int64) time.Time {
+	if expire == 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(expire)
+}
@@ -0,0 +1,164 @@
+package nitecache_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MysteriousPotato/nitecache"
+	"github.com/MysteriousPotato/nitecache/inmem"
+	test "github.com/MysteriousPotato/nitecache/test_utils"
+)
+
+func TestCache_SnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	self := nitecache.Member{ID: "1", Addr: test.GetUniqueAddr()}
+
+	c, err := nitecache.NewCache(self, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := nitecache.NewTable[string]("test").Build(c)
+
+	if err := table.Put(ctx, "key", "value", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Put(ctx, "ttl", "value", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Put(ctx, "expired", "value", time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	_, version, err := table.GetWithVersion(ctx, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 5)
+
+	buf := bytes.Buffer{}
+	if err := c.Snapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	test.TearDown(t, c)
+
+	// Simulate a restart
+	self.Addr = test.GetUniqueAddr()
+	c, err = nitecache.NewCache(self, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Values are restored once the table is built
+	if err := c.Restore(&buf); err != nil {
+		t.Fatal(err)
+	}
+	table = nitecache.NewTable[string]("test").Build(c)
+
+	for _, key := range []string{"key", "ttl"} {
+		if v, err := table.GetHot(key); err != nil || v != "value" {
+			t.Fatalf("expected value %q for key %q, got: %q, err: %v", "value", key, v, err)
+		}
+	}
+	if _, err := table.GetHot("expired"); !errors.Is(err, nitecache.ErrKeyNotFound) {
+		t.Fatalf("expected error %v for expired key, got: %v", nitecache.ErrKeyNotFound, err)
+	}
+	if _, gotVersion, err := table.GetWithVersion(ctx, "key"); err != nil || gotVersion != version {
+		t.Fatalf("expected version %d, got: %d, err: %v", version, gotVersion, err)
+	}
+
+	if err := c.Restore(bytes.NewReader([]byte("invalid"))); !errors.Is(err, nitecache.ErrInvalidSnapshot) {
+		t.Fatalf("expected error %v, got: %v", nitecache.ErrInvalidSnapshot, err)
+	}
+
+	test.TearDown(t, c)
+}
+
+func TestCache_SnapshotOpt(t *testing.T) {
+	ctx := context.Background()
+	self := nitecache.Member{ID: "1", Addr: test.GetUniqueAddr()}
+	path := filepath.Join(t.TempDir(), "snapshot")
+
+	c, err := nitecache.NewCache(self, nil, nitecache.SnapshotOpt(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := nitecache.NewTable[string]("test").Build(c)
+
+	if err := table.Put(ctx, "key", "value", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	test.TearDown(t, c)
+
+	self.Addr = test.GetUniqueAddr()
+	c, err = nitecache.NewCache(self, nil, nitecache.SnapshotOpt(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table = nitecache.NewTable[string]("test").Build(c)
+
+	if v, err := table.Get(ctx, "key"); err != nil || v != "value" {
+		t.Fatalf("expected value %q, got: %q, err: %v", "value", v, err)
+	}
+
+	test.TearDown(t, c)
+}
+
+// listlessStorage does not implement Values, so its items can't be listed
+type listlessStorage struct {
+	mu    sync.Mutex
+	items map[string]inmem.Item[[]byte]
+}
+
+func (s *listlessStorage) Put(key string, value inmem.Item[[]byte], _ ...inmem.Opt) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.items[key]
+	s.items[key] = value
+	return ok
+}
+
+func (s *listlessStorage) Evict(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.items[key]
+	delete(s.items, key)
+	return ok
+}
+
+func (s *listlessStorage) Get(key string, _ ...inmem.Opt) (inmem.Item[[]byte], bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[key]
+	return item, ok
+}
+
+func TestCache_SnapshotNotEnumerable(t *testing.T) {
+	c, err := nitecache.NewCache(nitecache.Member{ID: "1", Addr: test.GetUniqueAddr()}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDown(t, c)
+
+	table := nitecache.NewTable[string]("test").
+		WithStorage(&listlessStorage{items: map[string]inmem.Item[[]byte]{}}).
+		Build(c)
+
+	ctx := context.Background()
+	if err := table.Put(ctx, "key", "value", 0); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := table.Get(ctx, "key"); err != nil || v != "value" {
+		t.Fatalf("expected value %q, got: %q, err: %v", "value", v, err)
+	}
+
+	if err := c.Snapshot(&bytes.Buffer{}); !errors.Is(err, inmem.ErrNotEnumerable) {
+		t.Fatalf("expected error %v, got: %v", inmem.ErrNotEnumerable, err)
+	}
+}
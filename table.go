@@ -5,147 +5,418 @@ import (
 	"errors"
 	"fmt"
 	"github.com/MysteriousPotato/nitecache/inmem"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/MysteriousPotato/go-lockable"
+	"github.com/MysteriousPotato/nitecache/hashring"
 	"github.com/MysteriousPotato/nitecache/servicepb"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/singleflight"
 )
 
 var (
-	ErrRPCNotFound = errors.New("RPC not found")
-	ErrKeyNotFound = errors.New("key not found")
+	ErrRPCNotFound     = errors.New("RPC not found")
+	ErrKeyNotFound     = errors.New("key not found")
+	ErrVersionMismatch = errors.New("version mismatch")
+	ErrProcedureType   = errors.New("procedure argument or result type mismatch")
+	// ErrReplication is wrapped by errors returned when a write was applied on the owner, but could not be copied to every replica.
+	//
+	// The write must not be retried, since it already took effect. Values and versions are returned regardless.
+	ErrReplication = errors.New("unable to copy value to every replica")
+	// ErrNotExist can be returned (or wrapped) by getters to signal that no value exists for the key.
+	//
+	// Refer to [TableBuilder.WithNegativeTTL] for caching the absence of value.
+	ErrNotExist = inmem.ErrNotExist
 )
 
 // Procedure defines the type used for registering RPCs through [TableBuilder.WithProcedure].
 type Procedure[T any] func(ctx context.Context, v T, args []byte) (T, time.Duration, error)
 
+// EvictReason describes why a value was removed from the current node. Refer to [TableBuilder.WithOnEvict].
+type EvictReason int
+
+const (
+	// EvictCapacity means the value was evicted by the table's storage policy to make room for other values.
+	EvictCapacity = EvictReason(inmem.EvictCapacity)
+	// EvictExpired means the value expired before being removed, either by the expiration sweep or by the table's storage policy.
+	EvictExpired = EvictReason(inmem.EvictExpired)
+	// EvictExplicit means the value was evicted using [Table.Evict] or [Table.EvictAll].
+	EvictExplicit = EvictReason(inmem.EvictExplicit)
+	// EvictRebalanced means the value was moved to its new owners following a membership change.
+	EvictRebalanced = EvictExplicit + 1
+)
+
 type (
-	BatchEvictionErrs []batchEvictionErr
-	batchEvictionErr  struct {
+	BatchEvictionErrs []batchErr
+	BatchGetErrs      []batchErr
+	BatchPutErrs      []batchErr
+	batchErr          struct {
 		keys []string
 		err  error
 	}
 )
 
 type Table[T any] struct {
-	name       string
-	store      *inmem.Store[string, []byte]
-	hotStore   *inmem.Store[string, []byte]
-	codec      Codec[T]
-	getSF      *singleflight.Group
-	evictSF    *singleflight.Group
-	procedures map[string]Procedure[T]
-	metrics    *metrics
-	cache      *Cache
-	autofill   bool
+	name        string
+	store       *inmem.Store[string, []byte]
+	hotStore    *inmem.Store[string, []byte]
+	invalidator *invalidator
+	wal         *wal
+	writeLocks  lockable.Lockable[string]
+	codec       Codec[T]
+	getSF       *singleflight.Group
+	evictSF     *singleflight.Group
+	procedures  map[string]procedure[T]
+	typedCodecs map[string]procedureCodecs
+	metrics     *metrics
+	cache       *Cache
+	autofill    bool
+	onEvict     func(key string, value T, reason EvictReason)
+	writer      func(ctx context.Context, key string, v T) error
+	writeBehind *writeBehind[T]
+	// Expired items are still served for this long after their expiration, while being refreshed
+	staleWhileRevalidate time.Duration
 }
 
 type getResponse struct {
 	value inmem.Item[[]byte]
 	hit   bool
+	err   error
 }
 
 func (t *Table[T]) Get(ctx context.Context, key string) (T, error) {
+	v, _, err := t.GetWithVersion(ctx, key)
+	return v, err
+}
+
+// GetWithVersion behaves like [Table.Get], but also returns the version of the value.
+//
+// Versions are assigned by the owner every time a value is written, and can be used with [Table.CompareAndSwap].
+func (t *Table[T]) GetWithVersion(ctx context.Context, key string) (_ T, _ uint64, err error) {
 	if t.isZero() {
 		var empty T
-		return empty, ErrCacheDestroyed
+		return empty, 0, ErrCacheDestroyed
 	}
 
-	ownerID, err := t.cache.ring.GetOwner(key)
+	ctx, span := t.startSpan(ctx, "Get")
+	defer func() { endSpan(span, err) }()
+
+	owners, err := t.getReadOwners(key)
 	if err != nil {
-		return t.getEmptyValue(), err
+		return t.getEmptyValue(), 0, err
 	}
 
 	var item inmem.Item[[]byte]
 	var hit bool
-	if ownerID == t.cache.self.ID {
-		item, hit, err = t.getLocally(ctx, key)
-	} else {
-		client, err := t.cache.getClient(ownerID)
-		if err != nil {
-			return t.getEmptyValue(), err
-		}
-
-		item, hit, err = t.getFromPeer(ctx, key, client)
-		if err != nil {
-			return t.getEmptyValue(), err
+	for _, ownerID := range owners {
+		// Fallback to the next replica if the owner could not be reached,
+		// or if it missed the value without a getter to fill it, i.e. a replica that missed a write
+		span.SetAttributes(ownerAttr.String(ownerID))
+		item, hit, err = t.getFromOwner(ctx, key, ownerID)
+		if err != nil && !isUnreachable(err) || err == nil && (hit || t.autofill) {
+			break
 		}
 	}
 	if err != nil {
-		return t.getEmptyValue(), err
+		return t.getEmptyValue(), 0, err
 	}
+	span.SetAttributes(hitAttr.Bool(hit))
 
-	if !hit && !t.autofill || item.IsExpired() {
-		return t.getEmptyValue(), ErrKeyNotFound
+	if !hit && !t.autofill || t.isExpired(item) || item.Absent {
+		return t.getEmptyValue(), 0, ErrKeyNotFound
 	}
 
 	var v T
 	if err := t.codec.Decode(item.Value, &v); err != nil {
-		return t.getEmptyValue(), err
+		return t.getEmptyValue(), 0, err
 	}
 
-	return v, nil
+	return v, item.Version, nil
 }
 
-func (t *Table[T]) Put(ctx context.Context, key string, value T, ttl time.Duration) error {
+// Put stores the value on every replica responsible for the given key.
+//
+// The value is first stored on the owner, which assigns its version, and then copied to the remaining replicas.
+// If the owner is unreachable, the next replica takes its place.
+//
+// If some replicas fail to store the value, their errors are joined together and returned, wrapping [ErrReplication].
+func (t *Table[T]) Put(ctx context.Context, key string, value T, ttl time.Duration) (err error) {
 	if t.isZero() {
 		return ErrCacheDestroyed
 	}
 
-	ownerID, err := t.cache.ring.GetOwner(key)
+	ctx, span := t.startSpan(ctx, "Put")
+	defer func() { endSpan(span, err) }()
+
+	owners, err := t.getOwners(key)
 	if err != nil {
 		return err
 	}
+	span.SetAttributes(ownerAttr.String(owners[0]))
 
 	b, err := t.codec.Encode(value)
 	if err != nil {
 		return err
 	}
 
-	if ownerID == t.cache.self.ID {
-		if err := t.putLocally(key, t.store.NewItem(b, ttl)); err != nil {
-			return err
+	_, err = t.putToOwners(ctx, key, t.store.NewItem(b, ttl), owners)
+	return err
+}
+
+// CompareAndSwap stores the value only if the version of the current value matches expectedVersion, then returns the new version.
+//
+// Missing or expired values have a version of 0, so an expectedVersion of 0 can be used to store a value only if none exists.
+// Refer to [Table.GetWithVersion] for retrieving the current version.
+//
+// If the versions do not match, an error wrapping [ErrVersionMismatch] is returned.
+// If the value was swapped, but could not be copied to every replica, the new version is returned along with an error wrapping [ErrReplication].
+func (t *Table[T]) CompareAndSwap(
+	ctx context.Context,
+	key string,
+	expectedVersion uint64,
+	value T,
+	ttl time.Duration,
+) (_ uint64, err error) {
+	if t.isZero() {
+		return 0, ErrCacheDestroyed
+	}
+
+	ctx, span := t.startSpan(ctx, "CompareAndSwap")
+	defer func() { endSpan(span, err) }()
+
+	owners, err := t.getOwners(key)
+	if err != nil {
+		return 0, err
+	}
+	span.SetAttributes(ownerAttr.String(owners[0]))
+
+	b, err := t.codec.Encode(value)
+	if err != nil {
+		return 0, err
+	}
+
+	item := t.store.NewItem(b, ttl)
+	var swapped bool
+	if ownerID := owners[0]; ownerID == t.cache.self.ID {
+		if item, swapped, err = t.compareAndSwapLocally(ctx, key, expectedVersion, item); err != nil {
+			return 0, err
 		}
 	} else {
 		client, err := t.cache.getClient(ownerID)
+		if err != nil {
+			return 0, err
+		}
+
+		item, swapped, err = t.compareAndSwapFromPeer(ctx, key, expectedVersion, item, client)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if !swapped {
+		return 0, fmt.Errorf("%w: expected version %d, got %d", ErrVersionMismatch, expectedVersion, item.Version)
+	}
+
+	return item.Version, t.replicateToOwners(ctx, key, item, owners[1:])
+}
+
+// GetMany retrieves the values for the given keys.
+//
+// Keys owned by the same member are batched together and members are queried in parallel.
+// If a member is unreachable, its keys are retried against their next replica.
+// Without a getter, keys missed by a member are retried against their next replica as well.
+// Keys for which no value was found are omitted from the result.
+//
+// After the operation, a BatchGetErrs detailing which keys (if any) failed to be retrieved can be retrieved when checking the returned error.
+// Values that were successfully retrieved are returned regardless.
+// Example:
+//
+//	if errs, ok := err.(nitecache.BatchGetErrs); ok {
+//		keysThatFailed := errs.AffectedKeys()
+//	}
+func (t *Table[T]) GetMany(ctx context.Context, keys []string) (_ map[string]T, err error) {
+	if t.isZero() {
+		return nil, ErrCacheDestroyed
+	}
+
+	ctx, span := t.startSpan(ctx, "GetMany")
+	defer func() { endSpan(span, err) }()
+
+	readOwners := make(map[string][]string, len(keys))
+	ownerKeys := map[string][]string{}
+	for _, key := range keys {
+		owners, err := t.getReadOwners(key)
+		if err != nil {
+			return nil, err
+		}
+		readOwners[key] = owners
+		ownerKeys[owners[0]] = append(ownerKeys[owners[0]], key)
+	}
+
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	values := make(map[string]T, len(keys))
+	var errs BatchGetErrs
+
+	for len(ownerKeys) > 0 {
+		retries := map[string][]string{}
+
+		wg.Add(len(ownerKeys))
+		for ownerID, keys := range ownerKeys {
+			go func(ownerID string, keys []string) {
+				defer wg.Done()
+
+				items, err := t.getManyFromOwner(ctx, keys, ownerID)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				if err != nil {
+					// Fallback to the next replica only if the owner could not be reached
+					var failed []string
+					for _, key := range keys {
+						if next := readOwners[key][1:]; len(next) > 0 && isUnreachable(err) {
+							readOwners[key] = next
+							retries[next[0]] = append(retries[next[0]], key)
+							continue
+						}
+						failed = append(failed, key)
+					}
+					if failed != nil {
+						errs = append(errs, batchErr{keys: failed, err: err})
+					}
+					return
+				}
+
+				for key, res := range items {
+					if res.err != nil {
+						errs = append(errs, batchErr{keys: []string{key}, err: res.err})
+						continue
+					}
+					// Without a getter, a replica that missed a write may not hold the value
+					if next := readOwners[key][1:]; !res.hit && !t.autofill && len(next) > 0 {
+						readOwners[key] = next
+						retries[next[0]] = append(retries[next[0]], key)
+						continue
+					}
+					if !res.hit && !t.autofill || t.isExpired(res.value) || res.value.Absent {
+						continue
+					}
+
+					var v T
+					if err := t.codec.Decode(res.value.Value, &v); err != nil {
+						errs = append(errs, batchErr{keys: []string{key}, err: err})
+						continue
+					}
+					values[key] = v
+				}
+			}(ownerID, keys)
+		}
+		wg.Wait()
+
+		ownerKeys = retries
+	}
+
+	if errs != nil {
+		return values, errs
+	}
+	return values, nil
+}
+
+// PutMany stores the given values on every replica responsible for their keys.
+//
+// Keys owned by the same member are batched together and members are updated in parallel.
+// Values are first stored on their owners, which assign their versions, and then copied to the remaining replicas.
+//
+// After the operation, a BatchPutErrs detailing which keys (if any) failed to be stored can be retrieved when checking the returned error.
+func (t *Table[T]) PutMany(ctx context.Context, values map[string]T, ttl time.Duration) (err error) {
+	if t.isZero() {
+		return ErrCacheDestroyed
+	}
+
+	ctx, span := t.startSpan(ctx, "PutMany")
+	defer func() { endSpan(span, err) }()
+
+	ownerItems := map[string]map[string]inmem.Item[[]byte]{}
+	replicas := make(map[string][]string, len(values))
+	for key, value := range values {
+		owners, err := t.getOwners(key)
 		if err != nil {
 			return err
 		}
 
-		if err := t.putFromPeer(ctx, key, b, ttl, client); err != nil {
+		b, err := t.codec.Encode(value)
+		if err != nil {
 			return err
 		}
+
+		if _, ok := ownerItems[owners[0]]; !ok {
+			ownerItems[owners[0]] = map[string]inmem.Item[[]byte]{}
+		}
+		ownerItems[owners[0]][key] = t.store.NewItem(b, ttl)
+		replicas[key] = owners[1:]
+	}
+
+	versionedItems, errs := t.putManyToOwners(ctx, ownerItems)
+
+	replicaItems := map[string]map[string]inmem.Item[[]byte]{}
+	for key, item := range versionedItems {
+		for _, ownerID := range replicas[key] {
+			if _, ok := replicaItems[ownerID]; !ok {
+				replicaItems[ownerID] = map[string]inmem.Item[[]byte]{}
+			}
+			replicaItems[ownerID][key] = item
+		}
 	}
 
+	_, replicaErrs := t.putManyToOwners(ctx, replicaItems)
+	errs = append(errs, replicaErrs...)
+
+	if errs != nil {
+		return errs
+	}
 	return nil
 }
 
-func (t *Table[T]) Evict(ctx context.Context, key string) error {
+// Evict removes the entry for the given key from every replica.
+func (t *Table[T]) Evict(ctx context.Context, key string) (err error) {
 	if t.isZero() {
 		return ErrCacheDestroyed
 	}
 
-	ownerID, err := t.cache.ring.GetOwner(key)
+	ctx, span := t.startSpan(ctx, "Evict")
+	defer func() { endSpan(span, err) }()
+
+	owners, err := t.getOwners(key)
 	if err != nil {
 		return err
 	}
+	span.SetAttributes(ownerAttr.String(owners[0]))
 
-	if ownerID == t.cache.self.ID {
-		if err := t.evictLocally(key); err != nil {
-			return err
+	var errs []error
+	for _, ownerID := range owners {
+		if ownerID == t.cache.self.ID {
+			if err := t.evictLocally(key); err != nil {
+				errs = append(errs, err)
+			}
+			continue
 		}
-	} else {
+
 		client, err := t.cache.getClient(ownerID)
 		if err != nil {
-			return err
+			errs = append(errs, err)
+			continue
 		}
 
 		if err := t.evictFromPeer(ctx, key, client); err != nil {
-			return err
+			errs = append(errs, err)
 		}
 	}
-	return nil
+
+	return errors.Join(errs...)
 }
 
 // EvictAll attempts to remove all entries from the Table for the given keys.
@@ -159,11 +430,14 @@ func (t *Table[T]) Evict(ctx context.Context, key string) error {
 //		// Note that keys that AffectedKeys may return keys that were actually evicted successfully.
 //		keysThatFailed := errs.AffectedKeys()
 //	}
-func (t *Table[T]) EvictAll(ctx context.Context, keys []string) error {
+func (t *Table[T]) EvictAll(ctx context.Context, keys []string) (err error) {
 	if t.isZero() {
 		return ErrCacheDestroyed
 	}
 
+	ctx, span := t.startSpan(ctx, "EvictAll")
+	defer func() { endSpan(span, err) }()
+
 	type clientKeys struct {
 		client *client
 		keys   []string
@@ -172,38 +446,45 @@ func (t *Table[T]) EvictAll(ctx context.Context, keys []string) error {
 	var selfKeys []string
 	clientKeysMap := map[string]*clientKeys{}
 	for _, key := range keys {
-		ownerID, err := t.cache.ring.GetOwner(key)
+		owners, err := t.getOwners(key)
 		if err != nil {
 			return err
 		}
 
-		if ownerID == t.cache.self.ID {
-			selfKeys = append(selfKeys, key)
-			continue
-		}
-
-		if _, ok := clientKeysMap[ownerID]; !ok {
-			c, err := t.cache.getClient(ownerID)
-			if err != nil {
-				return err
+		for _, ownerID := range owners {
+			if ownerID == t.cache.self.ID {
+				selfKeys = append(selfKeys, key)
+				continue
 			}
 
-			clientKeysMap[ownerID] = &clientKeys{
-				client: c,
-				keys:   []string{key},
+			if _, ok := clientKeysMap[ownerID]; !ok {
+				c, err := t.cache.getClient(ownerID)
+				if err != nil {
+					return err
+				}
+
+				clientKeysMap[ownerID] = &clientKeys{
+					client: c,
+					keys:   []string{key},
+				}
+				continue
 			}
-			continue
-		}
 
-		clientKeysMap[ownerID].keys = append(clientKeysMap[ownerID].keys, key)
+			clientKeysMap[ownerID].keys = append(clientKeysMap[ownerID].keys, key)
+		}
 	}
 
-	t.evictAllLocally(selfKeys)
-
 	var errs BatchEvictionErrs
+	if err := t.evictAllLocally(selfKeys); err != nil {
+		errs = append(errs, batchErr{
+			keys: selfKeys,
+			err:  err,
+		})
+	}
+
 	for _, c := range clientKeysMap {
 		if err := t.evictAllFromPeer(ctx, c.keys, c.client); err != nil {
-			errs = append(errs, batchEvictionErr{
+			errs = append(errs, batchErr{
 				keys: c.keys,
 				err:  err,
 			})
@@ -219,33 +500,21 @@ func (t *Table[T]) EvictAll(ctx context.Context, keys []string) error {
 // Call calls an RPC previously registered through [TableBuilder.WithProcedure] on the owner node to update the value for the given key.
 //
 // Call acquires a lock exclusive to the given key until the RPC has finished executing.
-func (t *Table[T]) Call(ctx context.Context, key, function string, args []byte) (T, error) {
+//
+// The resulting value is then copied to the remaining replicas.
+// If some replicas fail to store it, the resulting value is returned along with an error wrapping [ErrReplication].
+func (t *Table[T]) Call(ctx context.Context, key, function string, args []byte) (_ T, err error) {
 	if t.isZero() {
 		var empty T
 		return empty, ErrCacheDestroyed
 	}
 
-	ownerID, err := t.cache.ring.GetOwner(key)
-	if err != nil {
-		return t.getEmptyValue(), err
-	}
-
-	var item inmem.Item[[]byte]
-	if ownerID == t.cache.self.ID {
-		item, err = t.callLocally(ctx, key, function, args)
-		if err != nil {
-			return t.getEmptyValue(), err
-		}
-	} else {
-		client, err := t.cache.getClient(ownerID)
-		if err != nil {
-			return t.getEmptyValue(), err
-		}
+	ctx, span := t.startSpan(ctx, "Call")
+	defer func() { endSpan(span, err) }()
 
-		item, err = t.callFromPeer(ctx, key, function, args, client)
-		if err != nil {
-			return t.getEmptyValue(), err
-		}
+	item, _, err := t.call(ctx, key, function, args)
+	if err != nil && !errors.Is(err, ErrReplication) {
+		return t.getEmptyValue(), err
 	}
 
 	if item.Value == nil {
@@ -264,7 +533,40 @@ func (t *Table[T]) Call(ctx context.Context, key, function string, args []byte)
 	return v, err
 }
 
-// GetHot looks up local cache if the current node is the owner, otherwise looks up  hot cache.
+// Call the procedure on the owner, then copy the resulting value to the remaining replicas.
+//
+// Returns the resulting item along with the encoded result of the procedure, if any.
+// Both are also returned if the procedure was applied, but the item could not be copied to every replica.
+func (t *Table[T]) call(ctx context.Context, key, function string, args []byte) (inmem.Item[[]byte], []byte, error) {
+	owners, err := t.getOwners(key)
+	if err != nil {
+		return inmem.Item[[]byte]{}, nil, err
+	}
+	trace.SpanFromContext(ctx).SetAttributes(ownerAttr.String(owners[0]), procedureAttr.String(function))
+
+	var item inmem.Item[[]byte]
+	var result []byte
+	if ownerID := owners[0]; ownerID == t.cache.self.ID {
+		item, result, err = t.callLocally(ctx, key, function, args)
+		if err != nil {
+			return inmem.Item[[]byte]{}, nil, err
+		}
+	} else {
+		client, err := t.cache.getClient(ownerID)
+		if err != nil {
+			return inmem.Item[[]byte]{}, nil, err
+		}
+
+		item, result, err = t.callFromPeer(ctx, key, function, args, client)
+		if err != nil {
+			return inmem.Item[[]byte]{}, nil, err
+		}
+	}
+
+	return item, result, t.replicateToOwners(ctx, key, item, owners[1:])
+}
+
+// GetHot looks up local cache if the current node is one of the owners, otherwise looks up  hot cache.
 //
 // GetHot does not call the getter to autofill cache, does not increment metrics and does not affect the main cache's LFU/LRU (if used).
 func (t *Table[T]) GetHot(key string) (T, error) {
@@ -273,14 +575,14 @@ func (t *Table[T]) GetHot(key string) (T, error) {
 		return empty, ErrCacheDestroyed
 	}
 
-	ownerID, err := t.cache.ring.GetOwner(key)
+	owners, err := t.getOwners(key)
 	if err != nil {
 		return t.getEmptyValue(), err
 	}
 
 	var item inmem.Item[[]byte]
 	var hit bool
-	if ownerID == t.cache.self.ID {
+	if slices.Contains(owners, t.cache.self.ID) {
 		item, hit, err = t.store.Get(context.Background(), key)
 		if err != nil {
 			return t.getEmptyValue(), err
@@ -292,7 +594,7 @@ func (t *Table[T]) GetHot(key string) (T, error) {
 		return t.getEmptyValue(), err
 	}
 
-	if !hit || item.IsExpired() {
+	if !hit || item.IsExpired() || item.Absent {
 		return t.getEmptyValue(), ErrKeyNotFound
 	}
 
@@ -312,10 +614,28 @@ func (t *Table[T]) GetMetrics() (Metrics, error) {
 	return t.metrics.getCopy(), nil
 }
 
+func (t *Table[T]) getMetrics() TableMetrics {
+	m := TableMetrics{
+		Metrics: t.metrics.getCopy(),
+		Items:   t.store.Len(),
+		Bytes:   t.store.Bytes(),
+		Expired: t.store.Expired(),
+	}
+	if t.hotStore != nil {
+		m.HotItems = t.hotStore.Len()
+		m.HotBytes = t.hotStore.Bytes()
+	}
+	return m
+}
+
 func (t *Table[T]) getLocally(ctx context.Context, key string) (inmem.Item[[]byte], bool, error) {
 	incGet(t.metrics, t.cache.metrics)
-	sfRes, err, _ := t.getSF.Do(key, func() (any, error) {
+
+	ctx, span := t.startSingleflightSpan(ctx)
+	sfRes, err, shared := t.getSF.Do(key, func() (any, error) {
+		start := time.Now()
 		item, hit, err := t.store.Get(ctx, key)
+		observeLocalGet(time.Since(start), t.metrics, t.cache.metrics)
 		if !hit {
 			incMiss(t.metrics, t.cache.metrics)
 		}
@@ -325,118 +645,668 @@ func (t *Table[T]) getLocally(ctx context.Context, key string) (inmem.Item[[]byt
 		}, err
 	})
 	res := sfRes.(getResponse)
+	endSingleflightSpan(span, res.hit, shared, err)
 
 	return res.value, res.hit, err
 }
 
-func (t *Table[T]) putLocally(key string, item inmem.Item[[]byte]) error {
+func (t *Table[T]) putLocally(ctx context.Context, key string, item inmem.Item[[]byte]) (inmem.Item[[]byte], error) {
 	incPut(t.metrics, t.cache.metrics)
-	t.store.Put(key, item)
-	return nil
-}
-
-func (t *Table[T]) evictLocally(key string) error {
-	incEvict(1, t.metrics, t.cache.metrics)
-	_, _, _ = t.evictSF.Do(key, func() (any, error) {
-		t.store.Evict(key)
-		return nil, nil
-	})
-	return nil
-}
-
-func (t *Table[T]) evictAllLocally(keys []string) {
-	incEvict(int64(len(keys)), t.metrics, t.cache.metrics)
-	t.store.EvictAll(keys)
-}
 
-func (t *Table[T]) callLocally(ctx context.Context, key, procedure string, args []byte) (inmem.Item[[]byte], error) {
-	incCalls(procedure, t.metrics, t.cache.metrics)
+	unlock := t.lockForWrite(key)
+	defer unlock()
 
-	// Can be access concurrently since no write is possible at this point
-	fn, ok := t.procedures[procedure]
-	if !ok {
-		return inmem.Item[[]byte]{}, ErrRPCNotFound
+	// Replicated items are already versioned, only the owner writes them
+	if item.Version == 0 {
+		if err := t.writeItem(ctx, key, item); err != nil {
+			return inmem.Item[[]byte]{}, err
+		}
 	}
 
-	return t.store.Update(ctx, key, args, func(ctx context.Context, value []byte, args []byte) ([]byte, time.Duration, error) {
-		var v T
-		if value != nil {
-			if err := t.codec.Decode(value, &v); err != nil {
-				return nil, 0, err
-			}
-		}
+	item = t.store.Put(key, item)
+	if err := t.appendToWAL(walOpPut, key, item); err != nil {
+		return inmem.Item[[]byte]{}, err
+	}
 
-		newValue, ttl, err := fn(ctx, v, args)
-		if err != nil {
-			return nil, 0, err
-		}
+	t.notifyPeers(key, &item)
+	return item, nil
+}
 
-		b, err := t.codec.Encode(newValue)
-		if err != nil {
-			return nil, 0, err
-		}
+func (t *Table[T]) compareAndSwapLocally(
+	ctx context.Context,
+	key string,
+	expectedVersion uint64,
+	item inmem.Item[[]byte],
+) (inmem.Item[[]byte], bool, error) {
+	incPut(t.metrics, t.cache.metrics)
 
-		return b, ttl, nil
-	})
-}
+	unlock := t.lockForWrite(key)
+	defer unlock()
 
-func (t *Table[T]) getFromPeer(ctx context.Context, key string, owner *client) (inmem.Item[[]byte], bool, error) {
-	sfRes, err, _ := t.getSF.Do(key, func() (any, error) {
-		res, err := owner.Get(ctx, &servicepb.GetRequest{
-			Table: t.name,
-			Key:   key,
-		})
-		if err != nil {
-			return getResponse{}, err
+	// The key is locked, so the swap can't fail once the versions are known to match
+	if t.store.Version(key) == expectedVersion {
+		if err := t.writeItem(ctx, key, item); err != nil {
+			return inmem.Item[[]byte]{}, false, err
 		}
+	}
 
-		item := inmem.Item[[]byte]{
-			Expire: time.UnixMicro(res.Item.Expire),
-			Value:  res.Item.Value,
-		}
+	item, swapped := t.store.CompareAndSwap(key, expectedVersion, item)
+	if !swapped {
+		return item, false, nil
+	}
 
-		if t.hotStore != nil {
-			t.hotStore.Put(key, item)
-		}
+	if err := t.appendToWAL(walOpPut, key, item); err != nil {
+		return inmem.Item[[]byte]{}, false, err
+	}
 
-		return getResponse{
-			value: item,
-			hit:   res.Hit,
-		}, nil
+	t.notifyPeers(key, &item)
+	return item, true, nil
+}
+
+func (t *Table[T]) evictLocally(key string) error {
+	incEvict(1, t.metrics, t.cache.metrics)
+	_, err, _ := t.evictSF.Do(key, func() (any, error) {
+		return nil, t.evictAndLog([]string{key}, EvictExplicit)
 	})
-	res := sfRes.(getResponse)
+	if err != nil {
+		return err
+	}
 
-	return res.value, res.hit, err
+	t.notifyPeers(key, nil)
+	return nil
 }
 
-func (t *Table[T]) putFromPeer(ctx context.Context, key string, b []byte, ttl time.Duration, owner *client) error {
-	item := t.store.NewItem(b, ttl)
-
-	if _, err := owner.Put(ctx, &servicepb.PutRequest{
-		Table: t.name,
-		Key:   key,
-		Item: &servicepb.Item{
-			Expire: item.Expire.UnixMicro(),
-			Value:  item.Value,
-		},
-	}); err != nil {
+func (t *Table[T]) evictAllLocally(keys []string) error {
+	incEvict(int64(len(keys)), t.metrics, t.cache.metrics)
+	if err := t.evictAndLog(keys, EvictExplicit); err != nil {
 		return err
 	}
 
-	if t.hotStore != nil {
-		t.hotStore.Put(key, item)
+	for _, key := range keys {
+		t.notifyPeers(key, nil)
 	}
-
 	return nil
 }
 
-func (t *Table[T]) evictFromPeer(ctx context.Context, key string, owner *client) error {
-	_, err, _ := t.evictSF.Do(key, func() (any, error) {
-		if _, err := owner.Evict(ctx, &servicepb.EvictRequest{
-			Table: t.name,
-			Key:   key,
-		}); err != nil {
-			return nil, err
+// Evict the keys from the local store, then report them to the eviction listener once every lock is released
+func (t *Table[T]) evictAndLog(keys []string, reason EvictReason) error {
+	removed := make(map[string]inmem.Item[[]byte], len(keys))
+	defer func() {
+		for _, key := range keys {
+			if item, ok := removed[key]; ok {
+				t.notifyEvicted(key, item, reason)
+			}
+		}
+	}()
+
+	if t.wal == nil {
+		for _, key := range keys {
+			if item, ok := t.store.Remove(key); ok {
+				removed[key] = item
+			}
+		}
+		return nil
+	}
+
+	// Keys are evicted one by one, so that each eviction is logged while holding its key
+	var errs []error
+	for _, key := range keys {
+		unlock := t.lockForWrite(key)
+		if item, ok := t.store.Remove(key); ok {
+			removed[key] = item
+		}
+		if err := t.appendToWAL(walOpEvict, key, inmem.Item[[]byte]{}); err != nil {
+			errs = append(errs, err)
+		}
+		unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// Decode the item and pass it to the writer, if any
+func (t *Table[T]) writeItem(ctx context.Context, key string, item inmem.Item[[]byte]) error {
+	if t.writer == nil {
+		return nil
+	}
+
+	var v T
+	if err := t.codec.Decode(item.Value, &v); err != nil {
+		return err
+	}
+	return t.write(ctx, key, v)
+}
+
+// Pass the value to the writer synchronously, or queue it if write-behind is enabled.
+//
+// Make sure to lock the key using lockForWrite before using this.
+func (t *Table[T]) write(ctx context.Context, key string, v T) error {
+	if t.writer == nil {
+		return nil
+	}
+	if t.writeBehind != nil {
+		t.writeBehind.queue(key, v)
+		return nil
+	}
+
+	ctx, span := t.cache.tracer.Start(ctx, "nitecache.writer", trace.WithAttributes(tableAttr.String(t.name)))
+	err := t.writer(ctx, key, v)
+	endSpan(span, err)
+	return err
+}
+
+// Decode the value removed from the local store and report it to the eviction listener, if any
+func (t *Table[T]) notifyEvicted(key string, item inmem.Item[[]byte], reason EvictReason) {
+	if t.onEvict == nil || item.Absent {
+		return
+	}
+
+	var v T
+	// Values are encoded using the same codec before being stored, so this shouldn't fail
+	if err := t.codec.Decode(item.Value, &v); err != nil {
+		return
+	}
+	t.onEvict(key, v, reason)
+}
+
+// Hold a lock on the key while applying, logging and writing a value, so that writes are logged and written in the same order they are applied.
+func (t *Table[T]) lockForWrite(key string) func() {
+	if t.wal == nil && t.writer == nil {
+		return func() {}
+	}
+
+	t.writeLocks.LockKey(key)
+	return func() {
+		t.writeLocks.UnlockKey(key)
+	}
+}
+
+func (t *Table[T]) appendToWAL(op byte, key string, item inmem.Item[[]byte]) error {
+	if t.wal == nil {
+		return nil
+	}
+
+	if err := t.wal.append(op, key, item); err != nil {
+		return fmt.Errorf("unable to append to write-ahead log: %w", err)
+	}
+	return nil
+}
+
+// Update the hot cache following a notification from the owner of the key.
+//
+// A nil item means the entry must be dropped.
+// Otherwise, the entry is only replaced if it is already in the hot cache, so that every member doesn't end up caching every key.
+//
+// With bounded loads, values filled by the current member for keys it doesn't own are dropped as well,
+// since writes are only applied on owners.
+func (t *Table[T]) invalidateLocally(key string, item *inmem.Item[[]byte]) {
+	if t.cache.loads != nil && t.autofill {
+		if owners, err := t.getOwners(key); err == nil && !slices.Contains(owners, t.cache.self.ID) {
+			_ = t.evictAndLog([]string{key}, EvictExplicit)
+		}
+	}
+
+	if t.hotStore == nil {
+		return
+	}
+
+	if item == nil {
+		t.hotStore.Evict(key)
+		return
+	}
+	if version := t.hotStore.Version(key); version != 0 {
+		t.hotStore.CompareAndSwap(key, version, *item)
+	}
+}
+
+// Queue a hot cache invalidation for other members if the current node is the primary owner of the key.
+//
+// A nil item means the key was evicted.
+func (t *Table[T]) notifyPeers(key string, item *inmem.Item[[]byte]) {
+	if t.invalidator == nil {
+		return
+	}
+
+	owners, err := t.getOwners(key)
+	if err != nil || owners[0] != t.cache.self.ID {
+		return
+	}
+	t.invalidator.notify(key, item)
+}
+
+func (t *Table[T]) callLocally(ctx context.Context, key, procedure string, args []byte) (inmem.Item[[]byte], []byte, error) {
+	incCalls(procedure, t.metrics, t.cache.metrics)
+
+	// Can be access concurrently since no write is possible at this point
+	fn, ok := t.procedures[procedure]
+	if !ok {
+		return inmem.Item[[]byte]{}, nil, ErrRPCNotFound
+	}
+
+	unlock := t.lockForWrite(key)
+	defer unlock()
+
+	var result []byte
+	item, err := t.store.Update(ctx, key, args, func(ctx context.Context, value []byte, args []byte) ([]byte, time.Duration, error) {
+		var v T
+		if value != nil {
+			if err := t.codec.Decode(value, &v); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		start := time.Now()
+		newValue, res, ttl, err := fn(ctx, v, args)
+		observeCalls(procedure, time.Since(start), t.metrics, t.cache.metrics)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		b, err := t.codec.Encode(newValue)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if err := t.write(ctx, key, newValue); err != nil {
+			return nil, 0, err
+		}
+		result = res
+		return b, ttl, nil
+	})
+	if err != nil {
+		return inmem.Item[[]byte]{}, nil, err
+	}
+
+	if err := t.appendToWAL(walOpPut, key, item); err != nil {
+		return inmem.Item[[]byte]{}, nil, err
+	}
+
+	t.notifyPeers(key, &item)
+	return item, result, nil
+}
+
+func (t *Table[T]) values() (map[string]inmem.Item[[]byte], error) {
+	if !t.store.Enumerable() {
+		return nil, fmt.Errorf("table %s: %w", t.name, inmem.ErrNotEnumerable)
+	}
+	return t.store.Values(), nil
+}
+
+func (t *Table[T]) restore(items map[string]inmem.Item[[]byte]) error {
+	var errs []error
+	for key, item := range items {
+		unlock := t.lockForWrite(key)
+		if err := t.appendToWAL(walOpPut, key, t.store.Put(key, item)); err != nil {
+			errs = append(errs, err)
+		}
+		unlock()
+	}
+	return errors.Join(errs...)
+}
+
+func (t *Table[T]) rebalanceLocally(key string, item inmem.Item[[]byte]) error {
+	unlock := t.lockForWrite(key)
+	defer unlock()
+
+	if !t.store.PutIfAbsent(key, item) {
+		return nil
+	}
+	return t.appendToWAL(walOpPut, key, item)
+}
+
+// Send every key to the members that became its owners, then drop it from the local store if the current node is no longer one of them.
+//
+// prev is a placement of the members of the previous hashring, used to skip owners which already held the key.
+// Keys are only dropped if every owner received them.
+// Keys of a storage that can't be enumerated are left in place, so that membership changes don't fail because of them.
+func (t *Table[T]) rebalance(ctx context.Context, prev hashring.Placement) error {
+	values, err := t.values()
+	if errors.Is(err, inmem.ErrNotEnumerable) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ownerItems := map[string]map[string]inmem.Item[[]byte]{}
+	kept := map[string]bool{}
+	for key, item := range values {
+		// Cached absences are left to expire rather than moved
+		if item.IsExpired() || item.Absent {
+			continue
+		}
+
+		owners, err := t.getOwners(key)
+		if err != nil {
+			return err
+		}
+
+		var prevOwners []string
+		if prev != nil {
+			if prevOwners, err = prev.GetOwners(key, t.cache.replicationFactor); err != nil {
+				return err
+			}
+		}
+
+		// Remaining owners only send the key to new owners, other members hand it over to every owner
+		isOwner := slices.Contains(owners, t.cache.self.ID)
+		kept[key] = isOwner
+		for _, ownerID := range owners {
+			if ownerID == t.cache.self.ID || isOwner && slices.Contains(prevOwners, ownerID) {
+				continue
+			}
+			if _, ok := ownerItems[ownerID]; !ok {
+				ownerItems[ownerID] = map[string]inmem.Item[[]byte]{}
+			}
+			ownerItems[ownerID][key] = item
+		}
+	}
+
+	var errs []error
+	moved := map[string]bool{}
+	for ownerID, items := range ownerItems {
+		client, err := t.cache.getClient(ownerID)
+		if err == nil {
+			err = t.rebalanceToPeer(ctx, items, client)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		for key := range items {
+			if ok, visited := moved[key]; !visited || ok {
+				moved[key] = err == nil
+			}
+		}
+	}
+
+	var keys []string
+	for key, ok := range moved {
+		if ok && !kept[key] {
+			keys = append(keys, key)
+		}
+	}
+	if err := t.evictAndLog(keys, EvictRebalanced); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (t *Table[T]) getFromOwner(ctx context.Context, key, ownerID string) (inmem.Item[[]byte], bool, error) {
+	if ownerID == t.cache.self.ID {
+		if t.cache.loads != nil {
+			defer t.cache.loads.track()()
+		}
+		return t.getLocally(ctx, key)
+	}
+
+	client, err := t.cache.getClient(ownerID)
+	if err != nil {
+		return inmem.Item[[]byte]{}, false, err
+	}
+
+	return t.getFromPeer(ctx, key, client)
+}
+
+func (t *Table[T]) getManyFromOwner(ctx context.Context, keys []string, ownerID string) (map[string]getResponse, error) {
+	if ownerID == t.cache.self.ID {
+		items := make(map[string]getResponse, len(keys))
+		for _, key := range keys {
+			item, hit, err := t.getLocally(ctx, key)
+			items[key] = getResponse{value: item, hit: hit, err: err}
+		}
+		return items, nil
+	}
+
+	client, err := t.cache.getClient(ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.getManyFromPeer(ctx, keys, client)
+}
+
+func (t *Table[T]) putManyToOwner(
+	ctx context.Context,
+	items map[string]inmem.Item[[]byte],
+	ownerID string,
+) (map[string]inmem.Item[[]byte], error) {
+	if ownerID == t.cache.self.ID {
+		versionedItems := make(map[string]inmem.Item[[]byte], len(items))
+		for key, item := range items {
+			versionedItem, err := t.putLocally(ctx, key, item)
+			if err != nil {
+				return nil, err
+			}
+			versionedItems[key] = versionedItem
+		}
+		return versionedItems, nil
+	}
+
+	client, err := t.cache.getClient(ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.putManyFromPeer(ctx, items, client)
+}
+
+// Update all owners in parallel and return the items that were successfully stored
+func (t *Table[T]) putManyToOwners(
+	ctx context.Context,
+	ownerItems map[string]map[string]inmem.Item[[]byte],
+) (map[string]inmem.Item[[]byte], BatchPutErrs) {
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	versionedItems := map[string]inmem.Item[[]byte]{}
+	var errs BatchPutErrs
+
+	wg.Add(len(ownerItems))
+	for ownerID, items := range ownerItems {
+		go func(ownerID string, items map[string]inmem.Item[[]byte]) {
+			defer wg.Done()
+
+			res, err := t.putManyToOwner(ctx, items, ownerID)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				keys := make([]string, 0, len(items))
+				for key := range items {
+					keys = append(keys, key)
+				}
+				errs = append(errs, batchErr{keys: keys, err: err})
+				return
+			}
+
+			for key, item := range res {
+				versionedItems[key] = item
+			}
+		}(ownerID, items)
+	}
+	wg.Wait()
+
+	return versionedItems, errs
+}
+
+func (t *Table[T]) putToOwner(ctx context.Context, key string, item inmem.Item[[]byte], ownerID string) (inmem.Item[[]byte], error) {
+	if ownerID == t.cache.self.ID {
+		return t.putLocally(ctx, key, item)
+	}
+
+	client, err := t.cache.getClient(ownerID)
+	if err != nil {
+		return inmem.Item[[]byte]{}, err
+	}
+
+	return t.putFromPeer(ctx, key, item, client)
+}
+
+// Store the item on the first reachable owner, so that it assigns a version, then copy the versioned item to the remaining owners
+func (t *Table[T]) putToOwners(ctx context.Context, key string, item inmem.Item[[]byte], owners []string) (inmem.Item[[]byte], error) {
+	var err error
+	for i, ownerID := range owners {
+		var versionedItem inmem.Item[[]byte]
+		if versionedItem, err = t.putToOwner(ctx, key, item, ownerID); err == nil {
+			return versionedItem, t.replicateToOwners(ctx, key, versionedItem, owners[i+1:])
+		}
+		if !isUnreachable(err) {
+			break
+		}
+	}
+	return inmem.Item[[]byte]{}, err
+}
+
+// Copy the item to the given owners. Returns an error wrapping [ErrReplication] if some of them failed to store it.
+func (t *Table[T]) replicateToOwners(ctx context.Context, key string, item inmem.Item[[]byte], owners []string) error {
+	var errs []error
+	for _, ownerID := range owners {
+		if _, err := t.putToOwner(ctx, key, item, ownerID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if errs != nil {
+		return fmt.Errorf("%w: %w", ErrReplication, errors.Join(errs...))
+	}
+	return nil
+}
+
+func (t *Table[T]) getFromPeer(ctx context.Context, key string, owner *client) (inmem.Item[[]byte], bool, error) {
+	ctx, span := t.startSingleflightSpan(ctx)
+	sfRes, err, shared := t.getSF.Do(key, func() (any, error) {
+		start := time.Now()
+		res, err := owner.Get(ctx, &servicepb.GetRequest{
+			Table: t.name,
+			Key:   key,
+		})
+		observeRemoteGet(time.Since(start), t.metrics, t.cache.metrics)
+		if err != nil {
+			return getResponse{}, err
+		}
+
+		item := fromPBItem(res.Item)
+		if t.hotStore != nil {
+			t.hotStore.Put(key, item)
+		}
+
+		return getResponse{
+			value: item,
+			hit:   res.Hit,
+		}, nil
+	})
+	res := sfRes.(getResponse)
+	endSingleflightSpan(span, res.hit, shared, err)
+
+	return res.value, res.hit, err
+}
+
+func (t *Table[T]) putFromPeer(ctx context.Context, key string, item inmem.Item[[]byte], owner *client) (inmem.Item[[]byte], error) {
+	res, err := owner.Put(ctx, &servicepb.PutRequest{
+		Table: t.name,
+		Key:   key,
+		Item:  toPBItem(item),
+	})
+	if err != nil {
+		return inmem.Item[[]byte]{}, err
+	}
+
+	item = fromPBItem(res.Item)
+	if t.hotStore != nil {
+		t.hotStore.Put(key, item)
+	}
+
+	return item, nil
+}
+
+func (t *Table[T]) compareAndSwapFromPeer(
+	ctx context.Context,
+	key string,
+	expectedVersion uint64,
+	item inmem.Item[[]byte],
+	owner *client,
+) (inmem.Item[[]byte], bool, error) {
+	res, err := owner.CompareAndSwap(ctx, &servicepb.CompareAndSwapRequest{
+		Table:           t.name,
+		Key:             key,
+		ExpectedVersion: expectedVersion,
+		Item:            toPBItem(item),
+	})
+	if err != nil {
+		return inmem.Item[[]byte]{}, false, err
+	}
+
+	item = fromPBItem(res.Item)
+	if res.Swapped && t.hotStore != nil {
+		t.hotStore.Put(key, item)
+	}
+
+	return item, res.Swapped, nil
+}
+
+func (t *Table[T]) getManyFromPeer(ctx context.Context, keys []string, owner *client) (map[string]getResponse, error) {
+	res, err := owner.GetMany(ctx, &servicepb.GetManyRequest{
+		Table: t.name,
+		Keys:  keys,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[string]getResponse, len(res.Items)+len(res.Errors))
+	for key, r := range res.Items {
+		item := fromPBItem(r.Item)
+		if t.hotStore != nil {
+			t.hotStore.Put(key, item)
+		}
+
+		items[key] = getResponse{value: item, hit: r.Hit}
+	}
+	for key, msg := range res.Errors {
+		items[key] = getResponse{err: errors.New(msg)}
+	}
+
+	return items, nil
+}
+
+func (t *Table[T]) putManyFromPeer(
+	ctx context.Context,
+	items map[string]inmem.Item[[]byte],
+	owner *client,
+) (map[string]inmem.Item[[]byte], error) {
+	pbItems := make(map[string]*servicepb.Item, len(items))
+	for key, item := range items {
+		pbItems[key] = toPBItem(item)
+	}
+
+	res, err := owner.PutMany(ctx, &servicepb.PutManyRequest{
+		Table: t.name,
+		Items: pbItems,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	versionedItems := make(map[string]inmem.Item[[]byte], len(res.Items))
+	for key, pbItem := range res.Items {
+		item := fromPBItem(pbItem)
+		if t.hotStore != nil {
+			t.hotStore.Put(key, item)
+		}
+		versionedItems[key] = item
+	}
+
+	return versionedItems, nil
+}
+
+func (t *Table[T]) evictFromPeer(ctx context.Context, key string, owner *client) error {
+	_, err, _ := t.evictSF.Do(key, func() (any, error) {
+		if _, err := owner.Evict(ctx, &servicepb.EvictRequest{
+			Table: t.name,
+			Key:   key,
+		}); err != nil {
+			return nil, err
 		}
 
 		if t.hotStore != nil {
@@ -467,7 +1337,7 @@ func (t *Table[T]) callFromPeer(
 	key, procedure string,
 	args []byte,
 	owner *client,
-) (inmem.Item[[]byte], error) {
+) (inmem.Item[[]byte], []byte, error) {
 	res, err := owner.Call(ctx, &servicepb.CallRequest{
 		Table:     t.name,
 		Key:       key,
@@ -475,19 +1345,38 @@ func (t *Table[T]) callFromPeer(
 		Args:      args,
 	})
 	if err != nil {
-		return inmem.Item[[]byte]{}, err
-	}
-
-	item := inmem.Item[[]byte]{
-		Expire: time.UnixMicro(res.Item.Expire),
-		Value:  res.Item.Value,
+		return inmem.Item[[]byte]{}, nil, err
 	}
 
+	item := fromPBItem(res.Item)
 	if t.hotStore != nil {
 		t.hotStore.Put(key, item)
 	}
 
-	return item, nil
+	return item, res.Result, nil
+}
+
+func (t *Table[T]) rebalanceToPeer(ctx context.Context, items map[string]inmem.Item[[]byte], owner *client) error {
+	ctx, cancel := context.WithTimeout(ctx, t.cache.timeout)
+	defer cancel()
+
+	stream, err := owner.Rebalance(ctx)
+	if err != nil {
+		return err
+	}
+
+	for key, item := range items {
+		if err := stream.Send(&servicepb.RebalanceRequest{
+			Table: t.name,
+			Key:   key,
+			Item:  toPBItem(item),
+		}); err != nil {
+			return err
+		}
+	}
+
+	_, err = stream.CloseAndRecv()
+	return err
 }
 
 func (t *Table[T]) getFromHotCache(key string) (inmem.Item[[]byte], bool, error) {
@@ -497,10 +1386,86 @@ func (t *Table[T]) getFromHotCache(key string) (inmem.Item[[]byte], bool, error)
 	return t.hotStore.Get(context.Background(), key)
 }
 
-func (t *Table[T]) tearDown() {
-	if t != nil {
-		*t = Table[T]{}
+func (t *Table[T]) getOwners(key string) ([]string, error) {
+	return t.cache.placement.GetOwners(key, t.cache.replicationFactor)
+}
+
+// Returns the members to read the given key from, starting with replicas in the same zone as the current member.
+//
+// If bounded loads are enabled, the member returned by the bounded loads placement comes first.
+// Without a getter, only replicas can serve the value, so the bounded owner is ignored unless it is one of them.
+func (t *Table[T]) getReadOwners(key string) ([]string, error) {
+	owners, err := t.getOwners(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if zone := t.cache.self.Zone; zone != "" {
+		var local, remote []string
+		for _, owner := range owners {
+			if t.cache.zones.Zone(owner) == zone {
+				local = append(local, owner)
+			} else {
+				remote = append(remote, owner)
+			}
+		}
+		owners = append(local, remote...)
+	}
+
+	bounded, ok := t.cache.placement.(*hashring.BoundedLoads)
+	if !ok {
+		return owners, nil
+	}
+
+	owner, err := bounded.GetOwner(key)
+	if err != nil {
+		return nil, err
+	}
+	if i := slices.Index(owners, owner); i >= 0 {
+		return append(append([]string{owner}, owners[:i]...), owners[i+1:]...), nil
+	}
+	if t.autofill {
+		return append([]string{owner}, owners...), nil
+	}
+	return owners, nil
+}
+
+// Stop background work that requires peers to be reachable
+func (t *Table[T]) stop() {
+	if t.invalidator != nil {
+		t.invalidator.stop()
+	}
+	t.store.Close()
+	if t.hotStore != nil {
+		t.hotStore.Close()
+	}
+}
+
+// Release resources once the table is no longer reachable from peers
+func (t *Table[T]) tearDown() error {
+	if t == nil {
+		return nil
 	}
+
+	var errs []error
+	// Peers can no longer write to the table at this point, so no pending write is left behind
+	if t.writeBehind != nil {
+		if err := t.writeBehind.stop(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if t.wal != nil {
+		if err := t.wal.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	*t = Table[T]{}
+	return errors.Join(errs...)
+}
+
+func (t *Table[T]) isExpired(item inmem.Item[[]byte]) bool {
+	return item.IsExpired() && (!t.autofill || time.Since(item.Expire) >= t.staleWhileRevalidate)
 }
 
 func (t *Table[T]) isZero() bool {
@@ -512,6 +1477,42 @@ func (t *Table[T]) getEmptyValue() T {
 	return v
 }
 
+func (b BatchGetErrs) Error() string {
+	var errs []string
+	for _, err := range b {
+		errs = append(errs, fmt.Sprintf("failed to get keys %v: %v", err.keys, err.err))
+	}
+	return strings.Join(errs, ",")
+}
+
+// AffectedKeys returns a list of keys that failed to be retrieved.
+func (b BatchGetErrs) AffectedKeys() []string {
+	var keys []string
+	for _, err := range b {
+		keys = append(keys, err.keys...)
+	}
+	return keys
+}
+
+func (b BatchPutErrs) Error() string {
+	var errs []string
+	for _, err := range b {
+		errs = append(errs, fmt.Sprintf("failed to put keys %v: %v", err.keys, err.err))
+	}
+	return strings.Join(errs, ",")
+}
+
+// AffectedKeys returns a list of keys owned by clients who returned an error.
+//
+// As a result, the list may contain keys that were successfully stored on other replicas.
+func (b BatchPutErrs) AffectedKeys() []string {
+	var keys []string
+	for _, err := range b {
+		keys = append(keys, err.keys...)
+	}
+	return keys
+}
+
 func (b BatchEvictionErrs) Error() string {
 	var errs []string
 	for _, err := range b {
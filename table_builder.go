@@ -2,27 +2,44 @@ package nitecache
 
 import (
 	"context"
+	"fmt"
+	"github.com/MysteriousPotato/go-lockable"
 	"github.com/MysteriousPotato/nitecache/inmem"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/singleflight"
 	"time"
 )
 
 type TableBuilder[T any] struct {
-	name       string
-	storage    inmem.Storage[string, []byte]
-	hotStorage inmem.Storage[string, []byte]
-	procedures map[string]Procedure[T]
-	getter     inmem.Getter[string, T]
-	codec      Codec[T]
+	name         string
+	storage      inmem.Storage[string, []byte]
+	hotStorage   inmem.Storage[string, []byte]
+	invalidation *HotCacheInvalidation
+	persistence  string
+	procedures   map[string]procedure[T]
+	typedCodecs  map[string]procedureCodecs
+	getter       inmem.Getter[string, T]
+	codec        Codec[T]
+	refreshAhead float64
+	staleWindow  time.Duration
+	negativeTTL  time.Duration
+	sweep        time.Duration
+	onEvict      func(key string, value T, reason EvictReason)
+	writer       func(ctx context.Context, key string, v T) error
+	writeBehind  *WriteBehind
 }
 
 func NewTable[T any](name string) *TableBuilder[T] {
 	return &TableBuilder[T]{
-		name:       name,
-		procedures: map[string]Procedure[T]{},
+		name:        name,
+		procedures:  map[string]procedure[T]{},
+		typedCodecs: map[string]procedureCodecs{},
 	}
 }
 
+// Sizer returns the cost of an entry in bytes, given its key and encoded value.
+type Sizer func(key string, value []byte) int
+
 func LFU(threshold int) inmem.Storage[string, []byte] {
 	return inmem.NewLFU[string, inmem.Item[[]byte]](threshold)
 }
@@ -30,15 +47,119 @@ func LRU(threshold int) inmem.Storage[string, []byte] {
 	return inmem.NewLRU[string, inmem.Item[[]byte]](threshold)
 }
 
+// TinyLFU uses a W-TinyLFU policy, which is resistant to scans and adapts to changes in popularity unlike [LFU].
+//
+// Refer to [inmem.TinyLFU] for more details.
+func TinyLFU(threshold int) inmem.Storage[string, []byte] {
+	return inmem.NewTinyLFU[string, inmem.Item[[]byte]](threshold)
+}
+
+// S3FIFO uses FIFO queues rather than LRU lists, so that gets never contend for a write lock.
+//
+// Refer to [inmem.S3FIFO] for more details.
+func S3FIFO(threshold int) inmem.Storage[string, []byte] {
+	return inmem.NewS3FIFO[string, inmem.Item[[]byte]](threshold)
+}
+
+// ARC adapts the balance between recency and frequency to the access pattern.
+//
+// Refer to [inmem.ARC] for more details.
+func ARC(threshold int) inmem.Storage[string, []byte] {
+	return inmem.NewARC[string, inmem.Item[[]byte]](threshold)
+}
+
+// LFUBytes behaves like [LFU], but evicts entries until their total cost fits within maxBytes rather than counting them.
+//
+// If sizer is nil, the cost of an entry is len(key)+len(value).
+// Entries costing more than maxBytes are not stored.
+func LFUBytes(maxBytes int, sizer Sizer) inmem.Storage[string, []byte] {
+	return inmem.NewLFUBytes[string, inmem.Item[[]byte]](maxBytes, itemSizer(sizer))
+}
+
+// LRUBytes behaves like [LRU], but evicts entries until their total cost fits within maxBytes rather than counting them.
+//
+// If sizer is nil, the cost of an entry is len(key)+len(value).
+// Entries costing more than maxBytes are not stored.
+func LRUBytes(maxBytes int, sizer Sizer) inmem.Storage[string, []byte] {
+	return inmem.NewLRUBytes[string, inmem.Item[[]byte]](maxBytes, itemSizer(sizer))
+}
+
+func itemSizer(sizer Sizer) inmem.Sizer[string, inmem.Item[[]byte]] {
+	if sizer == nil {
+		sizer = func(key string, value []byte) int {
+			return len(key) + len(value)
+		}
+	}
+	return func(key string, item inmem.Item[[]byte]) int {
+		return sizer(key, item.Value)
+	}
+}
+
 // WithGetter sets the auto cache filling function.
 func (tb *TableBuilder[T]) WithGetter(fn inmem.Getter[string, T]) *TableBuilder[T] {
 	tb.getter = fn
 	return tb
 }
 
+// WithWriter sets the function used to write values through to an external data source (i.e. a database).
+//
+// The writer is called by the owner of a key before storing a value written using [Table.Put], [Table.PutMany], [Table.CompareAndSwap] or [Table.Call].
+// If it fails, the value isn't stored and the error is returned.
+// Values copied to replicas, filled by the getter or rebalanced between members are not written.
+//
+// Writes are made synchronously, unless write-behind is enabled using [TableBuilder.WithWriteBehind].
+func (tb *TableBuilder[T]) WithWriter(fn func(ctx context.Context, key string, v T) error) *TableBuilder[T] {
+	tb.writer = fn
+	return tb
+}
+
+// WithWriteBehind makes the writer asynchronous.
+//
+// Values are stored immediately, while writes are queued on the owner, coalesced by key and flushed in batches.
+// Failed writes are retried with an exponential backoff, unless a newer value was written for the same key in the meantime.
+// Pending writes are flushed by [Cache.TearDown], which returns the errors of the writes that were dropped.
+//
+// Has no effect unless a writer is set using [TableBuilder.WithWriter].
+func (tb *TableBuilder[T]) WithWriteBehind(cfg WriteBehind) *TableBuilder[T] {
+	tb.writeBehind = &cfg
+	return tb
+}
+
+// WithRefreshAhead refreshes values in the background using the getter once they are accessed after the given fraction of their TTL has elapsed,
+// so that frequently accessed values never block on the getter when they expire.
+//
+// Fraction must be between 0 and 1, exclusively (i.e. 0.8 refreshes values accessed during the last 20% of their TTL).
+//
+// Only values filled by the getter or written on their owner are refreshed ahead.
+// Has no effect unless a getter is set using [TableBuilder.WithGetter].
+func (tb *TableBuilder[T]) WithRefreshAhead(fraction float64) *TableBuilder[T] {
+	tb.refreshAhead = fraction
+	return tb
+}
+
+// WithStaleWhileRevalidate returns expired values accessed within the given window after their expiration immediately,
+// while a single background refresh runs using the getter.
+//
+// Values accessed after the window are refreshed synchronously, as usual.
+// Has no effect unless a getter is set using [TableBuilder.WithGetter].
+func (tb *TableBuilder[T]) WithStaleWhileRevalidate(window time.Duration) *TableBuilder[T] {
+	tb.staleWindow = window
+	return tb
+}
+
+// WithNegativeTTL caches the absence of value on the owner for the given duration when the getter returns [ErrNotExist].
+//
+// Until the negative TTL expires, [Table.Get] returns [ErrKeyNotFound] without calling the getter again.
+// Writing a value for the key replaces the cached absence.
+// Has no effect unless a getter is set using [TableBuilder.WithGetter].
+func (tb *TableBuilder[T]) WithNegativeTTL(ttl time.Duration) *TableBuilder[T] {
+	tb.negativeTTL = ttl
+	return tb
+}
+
 // WithStorage specifies how to store values.
 //
-// Must be one of [LFU], [LRU] or nil.
+// Must be one of [LFU], [LRU], [TinyLFU], [S3FIFO], [ARC], [LFUBytes], [LRUBytes] or nil.
 //
 // if nil, the table will always grow unless keys are explicitly evicted.
 func (tb *TableBuilder[T]) WithStorage(storage inmem.Storage[string, []byte]) *TableBuilder[T] {
@@ -48,7 +169,11 @@ func (tb *TableBuilder[T]) WithStorage(storage inmem.Storage[string, []byte]) *T
 
 // WithProcedure Registers an RPC that can be called using [Table.Call].
 func (tb *TableBuilder[T]) WithProcedure(name string, function Procedure[T]) *TableBuilder[T] {
-	tb.procedures[name] = function
+	tb.procedures[name] = func(ctx context.Context, v T, args []byte) (T, []byte, time.Duration, error) {
+		newValue, ttl, err := function(ctx, v, args)
+		return newValue, nil, ttl, err
+	}
+	delete(tb.typedCodecs, name)
 	return tb
 }
 
@@ -56,12 +181,67 @@ func (tb *TableBuilder[T]) WithProcedure(name string, function Procedure[T]) *Ta
 //
 // If hot cache is enabled, a new cache will be populated with values gotten from other peers that can be accessed only through [Table.GetHot].
 //
-// The owner of the hot cache is responsible for keeping it up to date (i.e. calls to [Table.Put] and [Table.Evict] won't update hot cache of other peers).
+// The owner of the hot cache is responsible for keeping it up to date (i.e. calls to [Table.Put] and [Table.Evict] won't update hot cache of other peers),
+// unless invalidation is enabled using [TableBuilder.WithHotCacheInvalidation].
 func (tb *TableBuilder[T]) WithHotCache(storage inmem.Storage[string, []byte]) *TableBuilder[T] {
 	tb.hotStorage = storage
 	return tb
 }
 
+// WithHotCacheInvalidation enables hot cache invalidation.
+//
+// If enabled, the owner of a key broadcasts puts and evictions to every other member, which then drop or refresh the entry in their hot cache.
+// Notifications are batched, so hot caches may still serve stale data for up to [HotCacheInvalidation.FlushInterval].
+//
+// Tables with a getter always enable invalidation when using [BoundedLoadsOpt], to drop values filled by members that don't own them.
+//
+// Every member should use the same configuration for a given table.
+func (tb *TableBuilder[T]) WithHotCacheInvalidation(cfg HotCacheInvalidation) *TableBuilder[T] {
+	tb.invalidation = &cfg
+	return tb
+}
+
+// WithPersistence enables write-ahead logging of the values stored on the current node.
+//
+// Every write applied locally (i.e. [Table.Put], [Table.Evict], [Table.Call], etc.) is appended to a segmented, checksummed log in dir,
+// which is periodically compacted into a snapshot of the table.
+// The snapshot and the log are replayed when the table is built, so that values survive restarts.
+//
+// A partially written record at the end of the log (i.e. following a crash) is discarded.
+//
+// The log is synced to disk once every second, rather than on every write,
+// so writes applied during the last second before a crash of the host (but not of the process) may be lost.
+//
+// Storages that can't be enumerated (see [inmem.Storage]) can't be snapshotted, so their log is never compacted.
+//
+// dir must not be shared with other tables.
+// Errors opening or replaying the log are returned by [TableBuilder.TryBuild], and cause [TableBuilder.Build] to panic.
+func (tb *TableBuilder[T]) WithPersistence(dir string) *TableBuilder[T] {
+	tb.persistence = dir
+	return tb
+}
+
+// WithExpirationSweep removes expired values from the current node (and its hot cache, if enabled) in the background, at the given interval.
+//
+// Otherwise, expired values are only detected when accessed, and remain stored until they are replaced or evicted by the storage's policy.
+// This is especially important when using the default unbounded storage.
+//
+// The sweep is stopped by [Cache.TearDown].
+func (tb *TableBuilder[T]) WithExpirationSweep(interval time.Duration) *TableBuilder[T] {
+	tb.sweep = interval
+	return tb
+}
+
+// WithOnEvict calls fn for every value removed from the current node (but not from its hot cache), along with the reason of its removal.
+//
+// fn is called synchronously by the operation that removed the value, once the storage is unlocked, so it should not block for long.
+// Values replaced by a write and cached absences (see [TableBuilder.WithNegativeTTL]) are not reported.
+// Unless using [TableBuilder.WithExpirationSweep], expired values are only reported once evicted by the storage's policy.
+func (tb *TableBuilder[T]) WithOnEvict(fn func(key string, value T, reason EvictReason)) *TableBuilder[T] {
+	tb.onEvict = fn
+	return tb
+}
+
 // WithCodec overrides the default encoding/decoding behavior.
 //
 // Defaults to [BytesCodec] for []byte tables and [JsonCodec] for any other types.
@@ -71,34 +251,67 @@ func (tb *TableBuilder[T]) WithCodec(codec Codec[T]) *TableBuilder[T] {
 	return tb
 }
 
+// Build creates the table and registers it on the cache.
+//
+// Build panics if the write-ahead log (see [TableBuilder.WithPersistence]) fails to be opened or replayed.
+// Use [TableBuilder.TryBuild] to handle the error instead.
 func (tb *TableBuilder[T]) Build(c *Cache) *Table[T] {
+	t, err := tb.TryBuild(c)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// TryBuild creates the table and registers it on the cache,
+// unless its write-ahead log (see [TableBuilder.WithPersistence]) fails to be opened or replayed.
+func (tb *TableBuilder[T]) TryBuild(c *Cache) (*Table[T], error) {
+	var walItems map[string]inmem.Item[[]byte]
+	var w *wal
+	if tb.persistence != "" {
+		w = newWAL(tb.persistence, tb.name)
+
+		var err error
+		if walItems, err = w.open(); err != nil {
+			return nil, fmt.Errorf("unable to open write-ahead log: %w", err)
+		}
+	}
+
 	t := &Table[T]{
-		name:       tb.name,
-		getSF:      &singleflight.Group{},
-		evictSF:    &singleflight.Group{},
-		procedures: tb.procedures,
-		metrics:    newMetrics(),
-		autofill:   tb.getter != nil,
-		codec:      tb.codec,
-		cache:      c,
+		name:                 tb.name,
+		getSF:                &singleflight.Group{},
+		evictSF:              &singleflight.Group{},
+		procedures:           tb.procedures,
+		typedCodecs:          tb.typedCodecs,
+		metrics:              newMetrics(),
+		autofill:             tb.getter != nil,
+		codec:                tb.codec,
+		cache:                c,
+		staleWhileRevalidate: tb.staleWindow,
+		onEvict:              tb.onEvict,
+		writer:               tb.writer,
 	}
 
 	if t.codec == nil {
-		var v T
-		anyV := any(v)
-		if _, isByteSlice := anyV.([]byte); isByteSlice {
-			t.codec = any(StringCodec[[]byte]{}).(Codec[T])
-		} else if _, isString := anyV.(string); isString {
-			t.codec = any(StringCodec[string]{}).(Codec[T])
-		} else {
-			t.codec = &JsonCodec[T]{}
-		}
+		t.codec = defaultCodec[T]()
 	}
 
-	storageOpts := []inmem.StoreOpt[string, []byte]{inmem.WithStorage(tb.storage)}
+	storageOpts := []inmem.StoreOpt[string, []byte]{
+		inmem.WithStorage(tb.storage),
+		inmem.WithExpirationSweep[string, []byte](tb.sweep),
+	}
+	if tb.onEvict != nil {
+		storageOpts = append(storageOpts, inmem.WithOnEvict(func(key string, item inmem.Item[[]byte], reason inmem.EvictReason) {
+			t.notifyEvicted(key, item, EvictReason(reason))
+		}))
+	}
 	if tb.getter != nil {
 		storageOpts = append(storageOpts, inmem.WithGetter(func(ctx context.Context, key string) ([]byte, time.Duration, error) {
+			ctx, span := c.tracer.Start(ctx, "nitecache.getter", trace.WithAttributes(tableAttr.String(t.name)))
+			start := time.Now()
 			v, ttl, err := tb.getter(ctx, key)
+			observeGetter(time.Since(start), t.metrics, c.metrics)
+			endSpan(span, err)
 			if err != nil {
 				return nil, 0, err
 			}
@@ -110,16 +323,62 @@ func (tb *TableBuilder[T]) Build(c *Cache) *Table[T] {
 
 			return b, ttl, nil
 		}))
+		storageOpts = append(storageOpts,
+			inmem.WithRefreshAhead[string, []byte](tb.refreshAhead),
+			inmem.WithStaleWhileRevalidate[string, []byte](tb.staleWindow),
+			inmem.WithNegativeTTL[string, []byte](tb.negativeTTL),
+		)
 	}
 	t.store = inmem.NewStore[string, []byte](storageOpts...)
 
 	if tb.hotStorage != nil {
-		t.hotStore = inmem.NewStore[string, []byte](inmem.WithStorage(tb.hotStorage))
+		t.hotStore = inmem.NewStore[string, []byte](
+			inmem.WithStorage(tb.hotStorage),
+			inmem.WithExpirationSweep[string, []byte](tb.sweep),
+		)
+	}
+
+	invalidation := tb.invalidation
+	// Values filled by members that don't own them must be dropped once written on their owner
+	if invalidation == nil && c.loads != nil && tb.getter != nil {
+		invalidation = &HotCacheInvalidation{}
+	}
+	if invalidation != nil {
+		t.invalidator = newInvalidator(tb.name, c, *invalidation)
+	}
+
+	if tb.writer != nil && tb.writeBehind != nil {
+		t.writeBehind = newWriteBehind(tb.writer, *tb.writeBehind)
+	}
+
+	if tb.persistence != "" || tb.writer != nil {
+		t.writeLocks = lockable.New[string]()
+	}
+
+	if w != nil {
+		t.wal = w
+		for key, item := range walItems {
+			t.store.Put(key, item)
+		}
+
+		// Compaction requires a snapshot of every value
+		if t.store.Enumerable() {
+			w.start(t.values)
+		} else {
+			w.start(nil)
+		}
 	}
 
 	c.tablesMu.Lock()
 	defer c.tablesMu.Unlock()
 
+	// Load values restored from a snapshot before the table was built
+	if items, ok := c.restored[tb.name]; ok {
+		// Errors can only come from persistence, and are returned by subsequent writes as well
+		_ = t.restore(items)
+		delete(c.restored, tb.name)
+	}
+
 	c.tables[tb.name] = t
-	return t
+	return t, nil
 }
@@ -9,8 +9,9 @@ import (
 )
 
 var (
-	mu   = sync.Mutex{}
-	port = 50000
+	mu = sync.Mutex{}
+	// Below the ephemeral port range, so that outgoing connections between peers never take the port of a member yet to be started
+	port = 20000
 )
 
 type Cache interface {
@@ -34,13 +35,19 @@ func WaitForServer(t *testing.T, c Cache) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	healthCheckDone := make(chan struct{})
+	healthCheckDone := make(chan struct{}, 1)
 	go func() {
 		for {
 			if err := c.HealthCheckPeers(ctx); err == nil {
 				healthCheckDone <- struct{}{}
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Millisecond * 100):
 			}
-			time.Sleep(time.Millisecond * 100)
 		}
 	}()
 
@@ -51,3 +58,15 @@ func WaitForServer(t *testing.T, c Cache) {
 		t.Fatalf("clients health check failed after %s: %v", timeout.String(), ctx.Err())
 	}
 }
+
+type TearDowner interface {
+	TearDown() error
+}
+
+// TearDown tears down c, failing the test on error. Meant to be deferred.
+func TearDown(t *testing.T, c TearDowner) {
+	t.Helper()
+	if err := c.TearDown(); err != nil {
+		t.Fatal(err)
+	}
+}
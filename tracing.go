@@ -0,0 +1,211 @@
+package nitecache
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const tracerName = "github.com/MysteriousPotato/nitecache"
+
+var (
+	tableAttr     = attribute.Key("nitecache.table")
+	hitAttr       = attribute.Key("nitecache.hit")
+	ownerAttr     = attribute.Key("nitecache.owner")
+	procedureAttr = attribute.Key("nitecache.procedure")
+	// Whether the result of the singleflight was shared with other callers
+	sharedAttr = attribute.Key("nitecache.shared")
+
+	// Gossip RPCs are sent continuously, tracing them would only add noise
+	untracedMethods = map[string]bool{
+		"/servicepb.Service/Ping":    true,
+		"/servicepb.Service/PingReq": true,
+	}
+)
+
+// metadataCarrier adapts gRPC metadata to [propagation.TextMapCarrier].
+type metadataCarrier metadata.MD
+
+// TracingOpt sets the OpenTelemetry [trace.TracerProvider] used to trace [Table] operations and RPCs between peers.
+//
+// Span context is propagated to peers in gRPC metadata using the W3C Trace Context format.
+// Defaults to the global provider (see [otel.GetTracerProvider]).
+func TracingOpt(tp trace.TracerProvider) func(c *Cache) {
+	return func(c *Cache) {
+		c.tracer = tp.Tracer(tracerName)
+	}
+}
+
+func newTracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer(tracerName)
+}
+
+// Start a span for a Table operation
+func (t *Table[T]) startSpan(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return t.cache.tracer.Start(ctx, "nitecache.Table."+operation, trace.WithAttributes(tableAttr.String(t.name)))
+}
+
+// Start a span for a get deduplicated by the singleflight
+func (t *Table[T]) startSingleflightSpan(ctx context.Context) (context.Context, trace.Span) {
+	return t.cache.tracer.Start(ctx, "nitecache.singleflight", trace.WithAttributes(tableAttr.String(t.name)))
+}
+
+func endSingleflightSpan(span trace.Span, hit, shared bool, err error) {
+	span.SetAttributes(hitAttr.Bool(hit), sharedAttr.Bool(shared))
+	endSpan(span, err)
+}
+
+// End the span, recording err if not nil.
+//
+// Missing keys are not considered errors.
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, ErrKeyNotFound) && !errors.Is(err, ErrNotExist) {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Trace outgoing RPCs and inject the span context into the request metadata
+func tracingUnaryClientInterceptor(c *Cache) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if untracedMethods[method] {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		ctx, span := c.tracer.Start(ctx, strings.TrimPrefix(method, "/"), trace.WithSpanKind(trace.SpanKindClient))
+		err := invoker(injectSpanContext(ctx), method, req, reply, cc, opts...)
+		endRPCSpan(span, err)
+		return err
+	}
+}
+
+// Inject the span context into the stream metadata.
+//
+// Streams are not traced themselves, since they would need to be wrapped to know when they end.
+func tracingStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		return streamer(injectSpanContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+// Trace incoming RPCs as children of the span context found in the request metadata
+func tracingUnaryServerInterceptor(c *Cache) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if untracedMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, span := c.tracer.Start(
+			extractSpanContext(ctx),
+			strings.TrimPrefix(info.FullMethod, "/"),
+			trace.WithSpanKind(trace.SpanKindServer),
+		)
+		res, err := handler(ctx, req)
+		endRPCSpan(span, err)
+		return res, err
+	}
+}
+
+func tracingStreamServerInterceptor(c *Cache) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, span := c.tracer.Start(
+			extractSpanContext(ss.Context()),
+			strings.TrimPrefix(info.FullMethod, "/"),
+			trace.WithSpanKind(trace.SpanKindServer),
+		)
+		err := handler(srv, &tracedServerStream{ServerStream: ss, ctx: ctx})
+		endRPCSpan(span, err)
+		return err
+	}
+}
+
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func endRPCSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, status.Convert(err).Message())
+	}
+	span.End()
+}
+
+func injectSpanContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+
+	propagation.TraceContext{}.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func extractSpanContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, metadataCarrier(md))
+}
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
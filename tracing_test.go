@@ -0,0 +1,114 @@
+package nitecache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MysteriousPotato/nitecache"
+	test "github.com/MysteriousPotato/nitecache/test_utils"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[string], len(members))
+	for i, m := range members {
+		c, err := nitecache.NewCache(m, members,
+			nitecache.VirtualNodeOpt(1),
+			nitecache.HashFuncOpt(test.SimpleHashFunc),
+			nitecache.TracingOpt(tp),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer test.TearDown(t, c)
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+				return
+			}
+		}()
+
+		caches[i] = c
+		tables[i] = nitecache.NewTable[string]("test").
+			WithGetter(func(_ context.Context, key string) (string, time.Duration, error) {
+				return key, 0, nil
+			}).
+			Build(c)
+	}
+
+	for _, c := range caches {
+		test.WaitForServer(t, c)
+	}
+
+	// Key "2" is owned by member "2", so the getter must be called on the remote node
+	ctx, root := tp.Tracer("test").Start(context.Background(), "root")
+	if _, err := tables[0].Get(ctx, "2"); err != nil {
+		t.Fatal(err)
+	}
+	root.End()
+
+	spans := map[string][]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		if span.SpanContext.TraceID() == root.SpanContext().TraceID() {
+			spans[span.Name] = append(spans[span.Name], span)
+		}
+	}
+
+	expectedCounts := map[string]int{
+		"root":                   1,
+		"nitecache.Table.Get":    1,
+		"nitecache.singleflight": 2,
+		"servicepb.Service/Get":  2,
+		"nitecache.getter":       1,
+	}
+	for name, count := range expectedCounts {
+		if len(spans[name]) != count {
+			t.Fatalf("expected %d %q spans in the trace, got: %d", count, name, len(spans[name]))
+		}
+	}
+
+	getSpan := spans["nitecache.Table.Get"][0]
+	for _, attr := range []attribute.KeyValue{
+		attribute.String("nitecache.table", "test"),
+		attribute.String("nitecache.owner", "2"),
+		attribute.Bool("nitecache.hit", false),
+	} {
+		if !hasAttribute(getSpan, attr) {
+			t.Errorf("expected attribute %v, got: %v", attr, getSpan.Attributes)
+		}
+	}
+
+	for _, span := range spans["servicepb.Service/Get"] {
+		if span.SpanKind == trace.SpanKindServer && !span.Parent.IsRemote() {
+			t.Errorf("expected server span to have a remote parent")
+		}
+	}
+}
+
+func hasAttribute(span tracetest.SpanStub, attr attribute.KeyValue) bool {
+	for _, a := range span.Attributes {
+		if a == attr {
+			return true
+		}
+	}
+	return false
+}
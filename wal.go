@@ -0,0 +1,448 @@
+package nitecache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MysteriousPotato/nitecache/inmem"
+)
+
+const (
+	walSegmentExt  = ".wal"
+	walSnapshotExt = ".snapshot"
+	// Length and checksum of the record payload
+	walHeaderSize = 8
+)
+
+const (
+	walOpPut byte = iota + 1
+	walOpEvict
+)
+
+var (
+	errWALClosed        = errors.New("write-ahead log closed")
+	errWALRecordCorrupt = errors.New("corrupted write-ahead log record")
+
+	walChecksumTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+type (
+	// wal is a segmented append-only log of the writes applied to a table.
+	//
+	// Segments are periodically compacted into a snapshot of the table,
+	// so that only the writes appended since the last compaction need to be replayed on startup.
+	wal struct {
+		dir             string
+		table           string
+		maxSegmentSize  int64
+		syncInterval    time.Duration
+		compactInterval time.Duration
+		mu              sync.Mutex
+		segment         *os.File
+		segmentID       uint64
+		segmentSize     int64
+		dirty           bool
+		cancel          func()
+		done            chan struct{}
+	}
+	walRecord struct {
+		op   byte
+		key  string
+		item inmem.Item[[]byte]
+	}
+)
+
+func newWAL(dir, table string) *wal {
+	return &wal{
+		dir:             dir,
+		table:           table,
+		maxSegmentSize:  64 << 20,
+		syncInterval:    time.Second,
+		compactInterval: time.Minute * 5,
+	}
+}
+
+// Load the latest snapshot, replay the segments appended after it and open a new segment.
+//
+// A corrupted or partially written record ends its segment, which is truncated so that new records are never appended after it.
+func (w *wal) open() (map[string]inmem.Item[[]byte], error) {
+	if err := os.MkdirAll(w.dir, 0755); err != nil {
+		return nil, err
+	}
+
+	snapshotIDs, err := w.list(walSnapshotExt)
+	if err != nil {
+		return nil, err
+	}
+	segmentIDs, err := w.list(walSegmentExt)
+	if err != nil {
+		return nil, err
+	}
+
+	items := map[string]inmem.Item[[]byte]{}
+	var snapshotID uint64
+	if len(snapshotIDs) > 0 {
+		snapshotID = snapshotIDs[len(snapshotIDs)-1]
+		if items, err = w.readSnapshot(snapshotID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Files older than the latest snapshot are leftovers from an interrupted compaction
+	if err := w.removeBefore(snapshotID); err != nil {
+		return nil, err
+	}
+
+	w.segmentID = snapshotID
+	for _, id := range segmentIDs {
+		if id < snapshotID {
+			continue
+		}
+		if err := w.replay(id, items); err != nil {
+			return nil, err
+		}
+		w.segmentID = id
+	}
+
+	now := time.Now()
+	for key, item := range items {
+		if !item.Expire.IsZero() && item.Expire.Before(now) {
+			delete(items, key)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.unsafeRotate(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Sync and compact the log in the background until close is called.
+//
+// Compaction is disabled if values is nil.
+func (w *wal) start(values func() (map[string]inmem.Item[[]byte], error)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		syncTicker := time.NewTicker(w.syncInterval)
+		defer syncTicker.Stop()
+		var compact <-chan time.Time
+		if values != nil {
+			compactTicker := time.NewTicker(w.compactInterval)
+			defer compactTicker.Stop()
+			compact = compactTicker.C
+		}
+
+		for {
+			// Errors are retried on the next tick, appends keep working in the meantime
+			select {
+			case <-ctx.Done():
+				return
+			case <-syncTicker.C:
+				_ = w.sync()
+			case <-compact:
+				_ = w.compact(values)
+			}
+		}
+	}()
+}
+
+func (w *wal) append(op byte, key string, item inmem.Item[[]byte]) error {
+	record := encodeWALRecord(walRecord{op: op, key: key, item: item})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.segment == nil {
+		return errWALClosed
+	}
+
+	if w.segmentSize >= w.maxSegmentSize {
+		if err := w.unsafeRotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := w.segment.Write(record)
+	w.segmentSize += int64(n)
+	w.dirty = true
+	return err
+}
+
+func (w *wal) sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.segment == nil {
+		return errWALClosed
+	}
+	return w.segment.Sync()
+}
+
+// Write a snapshot of the table, then remove every segment covered by it.
+//
+// The snapshot is taken after switching to a new segment, so it may already contain some of the writes from the new segment.
+// This is fine since replaying a write on top of its own result yields the same value.
+func (w *wal) compact(values func() (map[string]inmem.Item[[]byte], error)) error {
+	w.mu.Lock()
+	if !w.dirty {
+		w.mu.Unlock()
+		return nil
+	}
+	if err := w.unsafeRotate(); err != nil {
+		w.mu.Unlock()
+		return err
+	}
+	id := w.segmentID
+	w.dirty = false
+	w.mu.Unlock()
+
+	if err := writeFileAtomic(w.path(id, walSnapshotExt), func(f io.Writer) error {
+		items, err := values()
+		if err != nil {
+			return err
+		}
+		return writeSnapshot(f, map[string]map[string]inmem.Item[[]byte]{w.table: items})
+	}); err != nil {
+		return err
+	}
+
+	return w.removeBefore(id)
+}
+
+// Stop the background work and close the current segment
+func (w *wal) close() error {
+	if w.cancel != nil {
+		w.cancel()
+		<-w.done
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.segment == nil {
+		return nil
+	}
+
+	err := errors.Join(w.segment.Sync(), w.segment.Close())
+	w.segment = nil
+	return err
+}
+
+// Make sure to lock mu before using this
+func (w *wal) unsafeRotate() error {
+	if w.segment != nil {
+		if err := w.segment.Sync(); err != nil {
+			return err
+		}
+		if err := w.segment.Close(); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(w.path(w.segmentID+1, walSegmentExt), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	w.segment = f
+	w.segmentID++
+	w.segmentSize = 0
+	return nil
+}
+
+func (w *wal) readSnapshot(id uint64) (map[string]inmem.Item[[]byte], error) {
+	f, err := os.Open(w.path(id, walSnapshotExt))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tables, err := readSnapshot(bufio.NewReader(f))
+	if err != nil {
+		return nil, err
+	}
+
+	if items, ok := tables[w.table]; ok {
+		return items, nil
+	}
+	return map[string]inmem.Item[[]byte]{}, nil
+}
+
+// Apply every valid record of a segment to items, then truncate the segment after the last valid record.
+func (w *wal) replay(id uint64, items map[string]inmem.Item[[]byte]) error {
+	f, err := os.OpenFile(w.path(id, walSegmentExt), os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var offset int64
+	for {
+		record, n, err := readWALRecord(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if errors.Is(err, errWALRecordCorrupt) {
+			return f.Truncate(offset)
+		}
+		if err != nil {
+			return err
+		}
+		offset += n
+
+		switch record.op {
+		case walOpPut:
+			items[record.key] = record.item
+		case walOpEvict:
+			delete(items, record.key)
+		}
+	}
+}
+
+// List the IDs of the files with the given extension, in ascending order
+func (w *wal) list(ext string) ([]uint64, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ext {
+			continue
+		}
+
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, ext), 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// Remove segments and snapshots older than the given ID
+func (w *wal) removeBefore(id uint64) error {
+	var errs []error
+	for _, ext := range []string{walSegmentExt, walSnapshotExt} {
+		ids, err := w.list(ext)
+		if err != nil {
+			return err
+		}
+
+		for _, fileID := range ids {
+			if fileID < id {
+				errs = append(errs, os.Remove(w.path(fileID, ext)))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *wal) path(id uint64, ext string) string {
+	return filepath.Join(w.dir, fmt.Sprintf("%020d%s", id, ext))
+}
+
+func encodeWALRecord(record walRecord) []byte {
+	buf := make([]byte, walHeaderSize, walHeaderSize+len(record.key)+len(record.item.Value)+32)
+	buf = append(buf, record.op)
+	buf = appendBytes(buf, []byte(record.key))
+	if record.op == walOpPut {
+		buf = appendBytes(buf, record.item.Value)
+		buf = binary.AppendVarint(buf, encodeExpire(record.item.Expire))
+		buf = binary.AppendUvarint(buf, record.item.Version)
+	}
+
+	payload := buf[walHeaderSize:]
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.Checksum(payload, walChecksumTable))
+	return buf
+}
+
+// Read the next record and return the number of bytes read.
+//
+// Returns io.EOF if there are no more records, or errWALRecordCorrupt if the record is incomplete or corrupted.
+func readWALRecord(r io.Reader) (walRecord, int64, error) {
+	header := make([]byte, walHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return walRecord{}, 0, errWALRecordCorrupt
+		}
+		return walRecord{}, 0, err
+	}
+
+	size := binary.LittleEndian.Uint32(header[0:4])
+	payload, err := io.ReadAll(io.LimitReader(r, int64(size)))
+	if err != nil {
+		return walRecord{}, 0, err
+	}
+	if len(payload) != int(size) || crc32.Checksum(payload, walChecksumTable) != binary.LittleEndian.Uint32(header[4:8]) {
+		return walRecord{}, 0, errWALRecordCorrupt
+	}
+
+	record, err := decodeWALRecord(payload)
+	if err != nil {
+		return walRecord{}, 0, fmt.Errorf("%w: %w", errWALRecordCorrupt, err)
+	}
+	return record, int64(walHeaderSize + len(payload)), nil
+}
+
+func decodeWALRecord(payload []byte) (walRecord, error) {
+	r := bytes.NewReader(payload)
+
+	op, err := r.ReadByte()
+	if err != nil {
+		return walRecord{}, err
+	}
+
+	key, err := readBytes(r)
+	if err != nil {
+		return walRecord{}, err
+	}
+
+	record := walRecord{op: op, key: string(key)}
+	switch op {
+	case walOpPut:
+		if record.item.Value, err = readBytes(r); err != nil {
+			return walRecord{}, err
+		}
+		expire, err := binary.ReadVarint(r)
+		if err != nil {
+			return walRecord{}, err
+		}
+		record.item.Expire = decodeExpire(expire)
+		if record.item.Version, err = binary.ReadUvarint(r); err != nil {
+			return walRecord{}, err
+		}
+	case walOpEvict:
+	default:
+		return walRecord{}, fmt.Errorf("unknown operation %d", op)
+	}
+
+	return record, nil
+}
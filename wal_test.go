@@ -0,0 +1,283 @@
+package nitecache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/MysteriousPotato/nitecache/inmem"
+	test "github.com/MysteriousPotato/nitecache/test_utils"
+)
+
+func TestWAL_Replay(t *testing.T) {
+	dir := t.TempDir()
+
+	w := newWAL(dir, "test")
+	if _, err := w.open(); err != nil {
+		t.Fatal(err)
+	}
+
+	expire := time.UnixMicro(time.Now().Add(time.Hour).UnixMicro())
+	records := []walRecord{
+		{op: walOpPut, key: "1", item: inmem.Item[[]byte]{Value: []byte("1"), Version: 1}},
+		{op: walOpPut, key: "2", item: inmem.Item[[]byte]{Value: []byte("2"), Version: 2, Expire: expire}},
+		{op: walOpPut, key: "3", item: inmem.Item[[]byte]{Value: []byte("3"), Version: 3}},
+		{op: walOpEvict, key: "3"},
+		{op: walOpPut, key: "1", item: inmem.Item[[]byte]{Value: []byte("updated"), Version: 4}},
+		{op: walOpPut, key: "4", item: inmem.Item[[]byte]{Value: []byte("4"), Version: 5}},
+		{op: walOpPut, key: "4", item: inmem.Item[[]byte]{Value: []byte("4"), Version: 6, Expire: time.UnixMicro(1)}},
+	}
+	for _, r := range records {
+		if err := w.append(r.op, r.key, r.item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	w = newWAL(dir, "test")
+	got, err := w.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.close()
+
+	expected := map[string]inmem.Item[[]byte]{
+		"1": {Value: []byte("updated"), Version: 4},
+		"2": {Value: []byte("2"), Version: 2, Expire: expire},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected: %v\ngot: %v", expected, got)
+	}
+}
+
+func TestWAL_TruncatedRecord(t *testing.T) {
+	dir := t.TempDir()
+
+	w := newWAL(dir, "test")
+	if _, err := w.open(); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"1", "2"} {
+		if err := w.append(walOpPut, key, inmem.Item[[]byte]{Value: []byte(key)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash while writing the last record
+	path := w.path(w.segmentID, walSegmentExt)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(path, info.Size()-2); err != nil {
+		t.Fatal(err)
+	}
+
+	w = newWAL(dir, "test")
+	got, err := w.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]inmem.Item[[]byte]{"1": {Value: []byte("1")}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected: %v\ngot: %v", expected, got)
+	}
+
+	// New records must not be lost behind the truncated one
+	if err := w.append(walOpPut, "3", inmem.Item[[]byte]{Value: []byte("3")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	w = newWAL(dir, "test")
+	got, err = w.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.close()
+
+	expected["3"] = inmem.Item[[]byte]{Value: []byte("3")}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected: %v\ngot: %v", expected, got)
+	}
+}
+
+func TestWAL_Compact(t *testing.T) {
+	dir := t.TempDir()
+
+	w := newWAL(dir, "test")
+	w.maxSegmentSize = 32
+	if _, err := w.open(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]inmem.Item[[]byte]{}
+	for i := 0; i < 10; i++ {
+		key := strconv.Itoa(i)
+		item := inmem.Item[[]byte]{Value: []byte("value-" + key)}
+		if err := w.append(walOpPut, key, item); err != nil {
+			t.Fatal(err)
+		}
+		expected[key] = item
+	}
+
+	segments, err := w.list(walSegmentExt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) < 2 {
+		t.Fatalf("expected segments to be rotated, got: %v", segments)
+	}
+
+	if err := w.compact(func() (map[string]inmem.Item[[]byte], error) {
+		return expected, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := w.list(walSnapshotExt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if segments, err = w.list(walSegmentExt); err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 || !reflect.DeepEqual(segments, snapshots) {
+		t.Fatalf("expected a single snapshot and segment with the same ID, got snapshots: %v, segments: %v", snapshots, segments)
+	}
+
+	if err := w.append(walOpEvict, "0", inmem.Item[[]byte]{}); err != nil {
+		t.Fatal(err)
+	}
+	delete(expected, "0")
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	w = newWAL(dir, "test")
+	got, err := w.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.close()
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected: %v\ngot: %v", expected, got)
+	}
+}
+
+func TestWAL_StartWithoutCompaction(t *testing.T) {
+	w := newWAL(t.TempDir(), "test")
+	w.maxSegmentSize = 32
+	w.compactInterval = time.Millisecond
+	if _, err := w.open(); err != nil {
+		t.Fatal(err)
+	}
+	w.start(nil)
+
+	for i := 0; i < 10; i++ {
+		key := strconv.Itoa(i)
+		if err := w.append(walOpPut, key, inmem.Item[[]byte]{Value: []byte("value-" + key)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := w.list(walSnapshotExt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 0 {
+		t.Fatalf("expected no snapshots, got: %v", snapshots)
+	}
+}
+
+func TestTable_WithPersistence(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	newTable := func() (*Cache, *Table[int]) {
+		c, err := NewCache(Member{ID: "1", Addr: test.GetUniqueAddr()}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return c, NewTable[int]("counters").
+			WithProcedure("increment", func(_ context.Context, v int, _ []byte) (int, time.Duration, error) {
+				return v + 1, 0, nil
+			}).
+			WithPersistence(dir).
+			Build(c)
+	}
+
+	c, table := newTable()
+	if err := table.Put(ctx, "1", 10, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Put(ctx, "2", 20, 0); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := table.Call(ctx, "1", "increment", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := table.Evict(ctx, "2"); err != nil {
+		t.Fatal(err)
+	}
+	test.TearDown(t, c)
+
+	c, table = newTable()
+	defer test.TearDown(t, c)
+
+	if v, err := table.Get(ctx, "1"); err != nil || v != 13 {
+		t.Fatalf("expected value %d, got: %d, err: %v", 13, v, err)
+	}
+	if _, err := table.Get(ctx, "2"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected error %v, got: %v", ErrKeyNotFound, err)
+	}
+}
+
+func TestTable_TryBuild(t *testing.T) {
+	// A file can't be used as the log directory
+	dir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(dir, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewCache(Member{ID: "1", Addr: test.GetUniqueAddr()}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.TearDown(t, c)
+
+	if _, err := NewTable[int]("try").WithPersistence(dir).TryBuild(c); err == nil {
+		t.Fatal("expected error opening the write-ahead log")
+	}
+	if _, err := c.getTable("try"); err == nil {
+		t.Fatal("expected table not to be registered")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Build to panic")
+		}
+	}()
+	NewTable[int]("build").WithPersistence(dir).Build(c)
+}
@@ -0,0 +1,205 @@
+package nitecache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type (
+	// WriteBehind configures how the owner of a key writes its values asynchronously.
+	//
+	// Refer to [TableBuilder.WithWriteBehind] for enabling write-behind on a [Table].
+	WriteBehind struct {
+		// Writes are coalesced by key and flushed once every FlushInterval.
+		//
+		// Defaults to 100ms
+		FlushInterval time.Duration
+		// Maximum number of writes made concurrently while flushing.
+		//
+		// Defaults to 10
+		BatchSize int
+		// Number of times a failed write is retried before being dropped. Negative values disable retries.
+		//
+		// Defaults to 3
+		MaxRetries int
+		// Delay before retrying a failed write, doubled after every attempt.
+		//
+		// Defaults to 100ms
+		RetryBackoff time.Duration
+		// If not nil, called with the last error of every write dropped after exhausting its retries.
+		OnError func(key string, err error)
+	}
+	// writeBehind queues the values written on the current node and flushes them to the table's writer.
+	writeBehind[T any] struct {
+		writer  func(ctx context.Context, key string, v T) error
+		cfg     WriteBehind
+		mu      sync.Mutex
+		pending map[string]pendingWrite[T]
+		cancel  func()
+		done    chan struct{}
+	}
+	pendingWrite[T any] struct {
+		value    T
+		attempts int
+		retryAt  time.Time
+	}
+)
+
+func newWriteBehind[T any](writer func(ctx context.Context, key string, v T) error, cfg WriteBehind) *writeBehind[T] {
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = time.Millisecond * 100
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 10
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = time.Millisecond * 100
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wb := &writeBehind[T]{
+		writer:  writer,
+		cfg:     cfg,
+		pending: map[string]pendingWrite[T]{},
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go wb.run(ctx)
+
+	return wb
+}
+
+// Queue a write for the given key, replacing any pending write for the same key.
+func (wb *writeBehind[T]) queue(key string, value T) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	wb.pending[key] = pendingWrite[T]{value: value}
+}
+
+func (wb *writeBehind[T]) run(ctx context.Context) {
+	defer close(wb.done)
+
+	ticker := time.NewTicker(wb.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Dropped writes are reported through OnError
+			_ = wb.flush(context.Background())
+		}
+	}
+}
+
+// Stop the flush loop, then flush the remaining writes, waiting for their retries if needed.
+//
+// Returns the errors of the writes that were dropped.
+func (wb *writeBehind[T]) stop() error {
+	wb.cancel()
+	<-wb.done
+
+	var errs []error
+	for {
+		if err := wb.flush(context.Background()); err != nil {
+			errs = append(errs, err)
+		}
+
+		nextRetry, ok := wb.nextRetry()
+		if !ok {
+			return errors.Join(errs...)
+		}
+		time.Sleep(time.Until(nextRetry))
+	}
+}
+
+// Write every pending value that is not waiting for a retry.
+//
+// Returns the errors of the writes that were dropped.
+func (wb *writeBehind[T]) flush(ctx context.Context) error {
+	now := time.Now()
+	due := map[string]pendingWrite[T]{}
+
+	wb.mu.Lock()
+	for key, w := range wb.pending {
+		if !w.retryAt.After(now) {
+			due[key] = w
+			delete(wb.pending, key)
+		}
+	}
+	wb.mu.Unlock()
+
+	var errs []error
+	errsMu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	sem := make(chan struct{}, wb.cfg.BatchSize)
+	for key, w := range due {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(key string, w pendingWrite[T]) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			err := wb.writer(ctx, key, w.value)
+			if err == nil || wb.retry(key, w) {
+				return
+			}
+
+			if wb.cfg.OnError != nil {
+				wb.cfg.OnError(key, err)
+			}
+			errsMu.Lock()
+			errs = append(errs, fmt.Errorf("unable to write key %q: %w", key, err))
+			errsMu.Unlock()
+		}(key, w)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Queue the failed write again after a backoff, unless a newer value was queued in the meantime.
+//
+// Returns false if the write must be dropped, since it is out of retries.
+func (wb *writeBehind[T]) retry(key string, w pendingWrite[T]) bool {
+	if w.attempts >= wb.cfg.MaxRetries {
+		return false
+	}
+
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	if _, ok := wb.pending[key]; ok {
+		return true
+	}
+
+	w.retryAt = time.Now().Add(wb.cfg.RetryBackoff << w.attempts)
+	w.attempts++
+	wb.pending[key] = w
+	return true
+}
+
+// Returns when the next pending write is due, or false if none is pending
+func (wb *writeBehind[T]) nextRetry() (time.Time, bool) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	var next time.Time
+	var ok bool
+	for _, w := range wb.pending {
+		if !ok || w.retryAt.Before(next) {
+			next, ok = w.retryAt, true
+		}
+	}
+	return next, ok
+}
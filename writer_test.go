@@ -0,0 +1,172 @@
+package nitecache_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MysteriousPotato/nitecache"
+	test "github.com/MysteriousPotato/nitecache/test_utils"
+)
+
+func TestWriteThrough(t *testing.T) {
+	members := []nitecache.Member{
+		{
+			ID:   "1",
+			Addr: test.GetUniqueAddr(),
+		}, {
+			ID:   "2",
+			Addr: test.GetUniqueAddr(),
+		},
+	}
+
+	errWrite := errors.New("write failed")
+	var mu sync.Mutex
+	var writes []string
+
+	caches := make([]*nitecache.Cache, len(members))
+	tables := make([]*nitecache.Table[string], len(members))
+	for i, m := range members {
+		c, err := nitecache.NewCache(m, members,
+			nitecache.VirtualNodeOpt(1),
+			nitecache.HashFuncOpt(test.SimpleHashFunc),
+			nitecache.ReplicationFactorOpt(2),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer test.TearDown(t, c)
+
+		go func() {
+			if err := c.ListenAndServe(); err != nil {
+				t.Error(err)
+				return
+			}
+		}()
+
+		caches[i] = c
+		tables[i] = nitecache.NewTable[string]("test").
+			WithWriter(func(_ context.Context, key string, v string) error {
+				if v == "invalid" {
+					return errWrite
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				writes = append(writes, key+"="+v)
+				return nil
+			}).
+			WithProcedure("append", func(_ context.Context, v string, args []byte) (string, time.Duration, error) {
+				return v + string(args), 0, nil
+			}).
+			Build(c)
+	}
+
+	for _, c := range caches {
+		test.WaitForServer(t, c)
+	}
+
+	ctx := context.Background()
+	if err := tables[0].Put(ctx, "2", "a", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tables[1].CompareAndSwap(ctx, "2", 0, "b", 0); !errors.Is(err, nitecache.ErrVersionMismatch) {
+		t.Fatalf("expected error %v, got: %v", nitecache.ErrVersionMismatch, err)
+	}
+	if _, err := tables[1].Call(ctx, "2", "append", []byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tables[1].Put(ctx, "1", "invalid", 0); err == nil || !strings.Contains(err.Error(), errWrite.Error()) {
+		t.Fatalf("expected error %v, got: %v", errWrite, err)
+	}
+
+	// Values are only written by their owner, not by replicas
+	expected := []string{"2=a", "2=ac"}
+	mu.Lock()
+	if !reflect.DeepEqual(writes, expected) {
+		t.Fatalf("expected writes %v\ngot %v", expected, writes)
+	}
+	mu.Unlock()
+
+	// Values that failed to be written are not stored
+	for _, table := range tables {
+		if _, err := table.Get(ctx, "1"); !errors.Is(err, nitecache.ErrKeyNotFound) {
+			t.Fatalf("expected error %v, got: %v", nitecache.ErrKeyNotFound, err)
+		}
+	}
+}
+
+func TestWriteBehind(t *testing.T) {
+	c, err := nitecache.NewCache(nitecache.Member{ID: "1", Addr: test.GetUniqueAddr()}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errWrite := errors.New("write failed")
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	writes := map[string]string{}
+	var dropped []string
+
+	table := nitecache.NewTable[string]("test").
+		WithWriter(func(_ context.Context, key string, v string) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			attempts[key]++
+			// Succeeds after being retried twice
+			if key == "flaky" && attempts[key] <= 2 || key == "invalid" {
+				return errWrite
+			}
+			writes[key] = v
+			return nil
+		}).
+		WithWriteBehind(nitecache.WriteBehind{
+			// Only flushed on tear down
+			FlushInterval: time.Hour,
+			MaxRetries:    2,
+			RetryBackoff:  time.Millisecond,
+			OnError: func(key string, err error) {
+				dropped = append(dropped, key)
+			},
+		}).
+		Build(c)
+
+	ctx := context.Background()
+	for _, v := range []string{"1", "2", "3"} {
+		if err := table.Put(ctx, "coalesced", v, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, key := range []string{"flaky", "invalid"} {
+		if err := table.Put(ctx, key, "1", 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mu.Lock()
+	if len(attempts) != 0 {
+		t.Fatalf("expected no write before flushing, got: %v", attempts)
+	}
+	mu.Unlock()
+
+	if err := c.TearDown(); !errors.Is(err, errWrite) {
+		t.Fatalf("expected error %v, got: %v", errWrite, err)
+	}
+
+	expectedWrites := map[string]string{"coalesced": "3", "flaky": "1"}
+	if !reflect.DeepEqual(writes, expectedWrites) {
+		t.Fatalf("expected writes %v\ngot %v", expectedWrites, writes)
+	}
+	expectedAttempts := map[string]int{"coalesced": 1, "flaky": 3, "invalid": 3}
+	if !reflect.DeepEqual(attempts, expectedAttempts) {
+		t.Fatalf("expected attempts %v\ngot %v", expectedAttempts, attempts)
+	}
+	if expected := []string{"invalid"}; !reflect.DeepEqual(dropped, expected) {
+		t.Fatalf("expected dropped writes %v\ngot %v", expected, dropped)
+	}
+}